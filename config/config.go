@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -14,24 +15,31 @@ import (
 
 // Config holds all configuration for a service
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`
-	Database DatabaseConfig `yaml:"database"`
-	Redis    RedisConfig    `yaml:"redis"`
-	JWT      JWTConfig      `yaml:"jwt"`
-	Services ServicesConfig `yaml:"services"`
-	CORS     CORSConfig     `yaml:"cors"`
-	S3       S3Config       `yaml:"s3"`
-	Logger   LoggerConfig   `yaml:"logger"`
+	Server     ServerConfig     `yaml:"server"`
+	Database   DatabaseConfig   `yaml:"database"`
+	Redis      RedisConfig      `yaml:"redis"`
+	JWT        JWTConfig        `yaml:"jwt"`
+	Services   ServicesConfig   `yaml:"services"`
+	CORS       CORSConfig       `yaml:"cors"`
+	S3         S3Config         `yaml:"s3"`
+	Logger     LoggerConfig     `yaml:"logger"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit"`
+	Worker     WorkerConfig     `yaml:"worker"`
+	Middleware MiddlewareConfig `yaml:"middleware"`
+	Tracing    TracingConfig    `yaml:"tracing"`
+
+	FeatureRollout map[string]int `yaml:"feature_rollout"` // feature -> percent (0-100) enabled
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port            int           `yaml:"port"`
-	Mode            string        `yaml:"mode"` // debug, release
-	BasePath        string        `yaml:"base_path"`
-	ReadTimeout     time.Duration `yaml:"read_timeout"`
-	WriteTimeout    time.Duration `yaml:"write_timeout"`
-	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	Port             int           `yaml:"port"`
+	Mode             string        `yaml:"mode"` // debug, release
+	BasePath         string        `yaml:"base_path"`
+	ReadTimeout      time.Duration `yaml:"read_timeout"`
+	WriteTimeout     time.Duration `yaml:"write_timeout"`
+	ShutdownTimeout  time.Duration `yaml:"shutdown_timeout"`
+	PreShutdownDelay time.Duration `yaml:"pre_shutdown_delay"` // readiness fails this long before shutdown
 }
 
 // DatabaseConfig holds database configuration
@@ -46,12 +54,15 @@ type DatabaseConfig struct {
 	MaxOpenConns    int           `yaml:"max_open_conns"`
 	MaxIdleConns    int           `yaml:"max_idle_conns"`
 	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ReadTimeout     time.Duration `yaml:"read_timeout"`  // per-query deadline for reads, see dbutil
+	WriteTimeout    time.Duration `yaml:"write_timeout"` // per-query deadline for writes, see dbutil
 }
 
 // RedisConfig holds Redis configuration
 type RedisConfig struct {
 	Host     string `yaml:"host"`
 	Port     int    `yaml:"port"`
+	Username string `yaml:"username"` // ACL user, Redis 6+
 	Password string `yaml:"password"`
 	DB       int    `yaml:"db"`
 	URL      string `yaml:"url"` // redis:// format
@@ -61,7 +72,11 @@ type RedisConfig struct {
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
 	Secret     string        `yaml:"secret"`
+	Secrets    []string      `yaml:"secrets"` // previous secrets still accepted for verification
 	ExpireTime time.Duration `yaml:"expire_time"`
+
+	// MaxExpireTime is the longest ExpireTime accepted without a warning
+	MaxExpireTime time.Duration `yaml:"max_expire_time"`
 }
 
 // ServicesConfig holds external service URLs
@@ -74,6 +89,10 @@ type ServicesConfig struct {
 	StorageServiceURL string        `yaml:"storage_service_url"`
 	VideoServiceURL   string        `yaml:"video_service_url"`
 	Timeout           time.Duration `yaml:"timeout"`
+
+	// PropagateHeaders are inbound request headers forwarded on calls to
+	// other services, e.g. the trace headers a service mesh relies on
+	PropagateHeaders []string `yaml:"propagate_headers"`
 }
 
 // CORSConfig holds CORS configuration
@@ -96,6 +115,53 @@ type LoggerConfig struct {
 	OutputPath string `yaml:"output_path"`
 }
 
+// RateLimitConfig holds request rate limiting configuration
+type RateLimitConfig struct {
+	// Enabled installs rate limiting in the router. The older
+	// middleware.enable_rate_limit key and ENABLE_RATE_LIMIT variable still set
+	// it, but rate_limit.enabled and RATE_LIMIT_ENABLED win when both are set.
+	Enabled           bool    `yaml:"enabled"`
+	RequestsPerSecond float64 `yaml:"requests_per_second"`
+	Burst             int     `yaml:"burst"`
+	FailClosed        bool    `yaml:"fail_closed"` // reject requests when the limiter store is unavailable
+
+	// Requests from ExemptCIDRs (CIDRs or single IPs), to ExemptPaths, or with
+	// one of ExemptAPIKeys in APIKeyHeader (X-API-Key by default) are never limited.
+	// ExemptCIDRs match the direct peer address, never X-Forwarded-For, so list
+	// the proxy's address to exempt traffic that arrives through one.
+	ExemptCIDRs   []string `yaml:"exempt_cidrs"`
+	ExemptPaths   []string `yaml:"exempt_paths"`
+	ExemptAPIKeys []string `yaml:"exempt_api_keys"`
+	APIKeyHeader  string   `yaml:"api_key_header"`
+}
+
+// WorkerConfig holds background job worker pool configuration
+type WorkerConfig struct {
+	Count        int           `yaml:"count"`
+	QueueSize    int           `yaml:"queue_size"`
+	PollInterval time.Duration `yaml:"poll_interval"`
+}
+
+// TracingConfig holds OpenTelemetry tracing configuration
+type TracingConfig struct {
+	ServiceName string  `yaml:"service_name"`
+	SampleRatio float64 `yaml:"sample_ratio"` // fraction (0-1) of root spans sampled
+
+	// RouteSampleRatios maps route patterns (c.FullPath()) to ratios overriding
+	// SampleRatio. Ratios of 0 and 1 also override a sampled or unsampled parent.
+	RouteSampleRatios map[string]float64 `yaml:"route_sample_ratios"`
+}
+
+// MiddlewareConfig toggles optional middleware installed by the router
+type MiddlewareConfig struct {
+	EnableMetrics bool `yaml:"enable_metrics"`
+	EnableCORS    bool `yaml:"enable_cors"`
+	EnableTracing bool `yaml:"enable_tracing"`
+}
+
+// defaultJWTExpireTime is the token lifetime used when none is configured
+const defaultJWTExpireTime = 24 * time.Hour
+
 // DefaultConfig returns default configuration values
 func DefaultConfig() *Config {
 	return &Config{
@@ -121,10 +187,22 @@ func DefaultConfig() *Config {
 			DB:   0,
 		},
 		JWT: JWTConfig{
-			ExpireTime: 24 * time.Hour,
+			ExpireTime:    defaultJWTExpireTime,
+			MaxExpireTime: 30 * 24 * time.Hour,
 		},
 		Services: ServicesConfig{
 			Timeout: 5 * time.Second,
+			PropagateHeaders: []string{
+				"X-Request-Id",
+				"X-B3-Traceid",
+				"X-B3-Spanid",
+				"X-B3-Parentspanid",
+				"X-B3-Sampled",
+				"X-B3-Flags",
+				"X-Ot-Span-Context",
+				"Traceparent",
+				"Tracestate",
+			},
 		},
 		CORS: CORSConfig{
 			AllowedOrigins: "*",
@@ -133,6 +211,23 @@ func DefaultConfig() *Config {
 			Level:      "info",
 			OutputPath: "stdout",
 		},
+		RateLimit: RateLimitConfig{
+			Enabled:           false,
+			RequestsPerSecond: 10,
+			Burst:             20,
+		},
+		Worker: WorkerConfig{
+			Count:        runtime.NumCPU(),
+			QueueSize:    100,
+			PollInterval: time.Second,
+		},
+		Middleware: MiddlewareConfig{
+			EnableMetrics: true,
+			EnableCORS:    true,
+		},
+		Tracing: TracingConfig{
+			SampleRatio: 1,
+		},
 	}
 }
 
@@ -144,7 +239,7 @@ func Load(configPath string) (*Config, error) {
 	if configPath != "" {
 		data, err := os.ReadFile(configPath)
 		if err == nil {
-			if err := yaml.Unmarshal(data, cfg); err != nil {
+			if err := parseYAML(data, cfg); err != nil {
 				return nil, fmt.Errorf("failed to parse config file: %w", err)
 			}
 		}
@@ -156,27 +251,74 @@ func Load(configPath string) (*Config, error) {
 	return cfg, nil
 }
 
+// parseYAML decodes a config file into cfg, mapping the deprecated
+// middleware.enable_rate_limit key onto RateLimit.Enabled unless the file
+// also sets rate_limit.enabled
+func parseYAML(data []byte, cfg *Config) error {
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return err
+	}
+
+	var legacy struct {
+		RateLimit struct {
+			Enabled *bool `yaml:"enabled"`
+		} `yaml:"rate_limit"`
+		Middleware struct {
+			EnableRateLimit *bool `yaml:"enable_rate_limit"`
+		} `yaml:"middleware"`
+	}
+	if err := yaml.Unmarshal(data, &legacy); err != nil {
+		return err
+	}
+	if legacy.Middleware.EnableRateLimit != nil && legacy.RateLimit.Enabled == nil {
+		cfg.RateLimit.Enabled = *legacy.Middleware.EnableRateLimit
+	}
+	return nil
+}
+
 // LoadFromEnv overrides configuration with environment variables
 func (c *Config) LoadFromEnv() {
+	c.LoadFromEnvWithPrefix("")
+}
+
+// LoadFromEnvWithPrefix overrides configuration with environment variables,
+// applying unprefixed names (PORT, DB_HOST) first and then PREFIX_-prefixed
+// ones (AUTH_PORT, AUTH_DB_HOST), so services sharing a container can coexist.
+// Precedence, highest first: prefixed variable, unprefixed variable, YAML file, defaults.
+func (c *Config) LoadFromEnvWithPrefix(prefix string) {
+	c.loadEnv(os.Getenv)
+	if prefix == "" {
+		return
+	}
+	if !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+	c.loadEnv(func(name string) string {
+		return os.Getenv(prefix + name)
+	})
+}
+
+// loadEnv overrides configuration with the non-empty values returned by getenv
+func (c *Config) loadEnv(getenv func(string) string) {
 	// Server
-	if port := os.Getenv("PORT"); port != "" {
+	if port := getenv("PORT"); port != "" {
 		if p, err := strconv.Atoi(port); err == nil {
 			c.Server.Port = p
 		}
 	}
-	if port := os.Getenv("SERVER_PORT"); port != "" {
+	if port := getenv("SERVER_PORT"); port != "" {
 		if p, err := strconv.Atoi(port); err == nil {
 			c.Server.Port = p
 		}
 	}
-	if mode := os.Getenv("SERVER_MODE"); mode != "" {
+	if mode := getenv("SERVER_MODE"); mode != "" {
 		c.Server.Mode = mode
 	}
-	if mode := os.Getenv("GIN_MODE"); mode != "" {
+	if mode := getenv("GIN_MODE"); mode != "" {
 		c.Server.Mode = mode
 	}
 	// ENV alias: dev→debug, prod→release
-	if env := os.Getenv("ENV"); env != "" {
+	if env := getenv("ENV"); env != "" {
 		switch env {
 		case "dev":
 			c.Server.Mode = "debug"
@@ -186,107 +328,234 @@ func (c *Config) LoadFromEnv() {
 			c.Server.Mode = env
 		}
 	}
-	if basePath := os.Getenv("SERVER_BASE_PATH"); basePath != "" {
+	if basePath := getenv("SERVER_BASE_PATH"); basePath != "" {
 		c.Server.BasePath = basePath
 	}
+	if delay := getenv("SERVER_PRE_SHUTDOWN_DELAY"); delay != "" {
+		if d, err := time.ParseDuration(delay); err == nil {
+			c.Server.PreShutdownDelay = d
+		}
+	}
 
 	// Database - DATABASE_URL takes precedence
-	if dbURL := os.Getenv("DATABASE_URL"); dbURL != "" {
+	if dbURL := getenv("DATABASE_URL"); dbURL != "" {
 		c.Database.URL = dbURL
 		c.parseDatabaseURL(dbURL)
 	}
-	if host := os.Getenv("DB_HOST"); host != "" {
+	if host := getenv("DB_HOST"); host != "" {
 		c.Database.Host = host
 	}
-	if port := os.Getenv("DB_PORT"); port != "" {
+	if port := getenv("DB_PORT"); port != "" {
 		c.Database.Port = port
 	}
-	if user := os.Getenv("DB_USER"); user != "" {
+	if user := getenv("DB_USER"); user != "" {
 		c.Database.User = user
 	}
-	if password := os.Getenv("DB_PASSWORD"); password != "" {
+	if password := getenv("DB_PASSWORD"); password != "" {
 		c.Database.Password = password
 	}
-	if dbname := os.Getenv("DB_NAME"); dbname != "" {
+	if dbname := getenv("DB_NAME"); dbname != "" {
 		c.Database.DBName = dbname
 	}
+	if timeout := getenv("DB_READ_TIMEOUT"); timeout != "" {
+		if d, err := time.ParseDuration(timeout); err == nil {
+			c.Database.ReadTimeout = d
+		}
+	}
+	if timeout := getenv("DB_WRITE_TIMEOUT"); timeout != "" {
+		if d, err := time.ParseDuration(timeout); err == nil {
+			c.Database.WriteTimeout = d
+		}
+	}
 
-	// Redis
-	if host := os.Getenv("REDIS_HOST"); host != "" {
+	// Redis - REDIS_URL takes precedence
+	if redisURL := getenv("REDIS_URL"); redisURL != "" {
+		c.Redis.URL = redisURL
+		c.parseRedisURL(redisURL)
+	}
+	if host := getenv("REDIS_HOST"); host != "" {
 		c.Redis.Host = host
 	}
-	if port := os.Getenv("REDIS_PORT"); port != "" {
+	if port := getenv("REDIS_PORT"); port != "" {
 		if p, err := strconv.Atoi(port); err == nil {
 			c.Redis.Port = p
 		}
 	}
-	if password := os.Getenv("REDIS_PASSWORD"); password != "" {
+	if password := getenv("REDIS_PASSWORD"); password != "" {
 		c.Redis.Password = password
 	}
-	if redisURL := os.Getenv("REDIS_URL"); redisURL != "" {
-		c.Redis.URL = redisURL
-	}
 
 	// JWT
-	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+	if secret := getenv("JWT_SECRET"); secret != "" {
 		c.JWT.Secret = secret
 	}
-	if secret := os.Getenv("SECRET_KEY"); secret != "" {
+	if secret := getenv("SECRET_KEY"); secret != "" {
 		c.JWT.Secret = secret
 	}
+	if secrets := getenv("JWT_SECRETS"); secrets != "" {
+		c.JWT.Secrets = splitList(secrets)
+	}
+	if expire := getenv("JWT_EXPIRE_TIME"); expire != "" {
+		if d, err := time.ParseDuration(expire); err == nil {
+			c.JWT.ExpireTime = d
+		}
+	}
+	if maxExpire := getenv("JWT_MAX_EXPIRE_TIME"); maxExpire != "" {
+		if d, err := time.ParseDuration(maxExpire); err == nil {
+			c.JWT.MaxExpireTime = d
+		}
+	}
 
 	// Services
-	if url := os.Getenv("AUTH_SERVICE_URL"); url != "" {
+	if url := getenv("AUTH_SERVICE_URL"); url != "" {
 		c.Services.AuthServiceURL = url
 	}
-	if url := os.Getenv("USER_SERVICE_URL"); url != "" {
+	if url := getenv("USER_SERVICE_URL"); url != "" {
 		c.Services.UserServiceURL = url
 	}
-	if url := os.Getenv("BOARD_SERVICE_URL"); url != "" {
+	if url := getenv("BOARD_SERVICE_URL"); url != "" {
 		c.Services.BoardServiceURL = url
 	}
-	if url := os.Getenv("CHAT_SERVICE_URL"); url != "" {
+	if url := getenv("CHAT_SERVICE_URL"); url != "" {
 		c.Services.ChatServiceURL = url
 	}
-	if url := os.Getenv("NOTI_SERVICE_URL"); url != "" {
+	if url := getenv("NOTI_SERVICE_URL"); url != "" {
 		c.Services.NotiServiceURL = url
 	}
-	if url := os.Getenv("STORAGE_SERVICE_URL"); url != "" {
+	if url := getenv("STORAGE_SERVICE_URL"); url != "" {
 		c.Services.StorageServiceURL = url
 	}
-	if url := os.Getenv("VIDEO_SERVICE_URL"); url != "" {
+	if url := getenv("VIDEO_SERVICE_URL"); url != "" {
 		c.Services.VideoServiceURL = url
 	}
+	if headers := getenv("SERVICES_PROPAGATE_HEADERS"); headers != "" {
+		c.Services.PropagateHeaders = splitList(headers)
+	}
 
 	// CORS
-	if origins := os.Getenv("CORS_ORIGINS"); origins != "" {
+	if origins := getenv("CORS_ORIGINS"); origins != "" {
 		c.CORS.AllowedOrigins = origins
 	}
-	if origins := os.Getenv("CORS_ALLOWED_ORIGINS"); origins != "" {
+	if origins := getenv("CORS_ALLOWED_ORIGINS"); origins != "" {
 		c.CORS.AllowedOrigins = origins
 	}
 
 	// S3
-	if bucket := os.Getenv("S3_BUCKET"); bucket != "" {
+	if bucket := getenv("S3_BUCKET"); bucket != "" {
 		c.S3.Bucket = bucket
 	}
-	if region := os.Getenv("S3_REGION"); region != "" {
+	if region := getenv("S3_REGION"); region != "" {
 		c.S3.Region = region
 	}
-	if accessKey := os.Getenv("S3_ACCESS_KEY"); accessKey != "" {
+	if accessKey := getenv("S3_ACCESS_KEY"); accessKey != "" {
 		c.S3.AccessKey = accessKey
 	}
-	if secretKey := os.Getenv("S3_SECRET_KEY"); secretKey != "" {
+	if secretKey := getenv("S3_SECRET_KEY"); secretKey != "" {
 		c.S3.SecretKey = secretKey
 	}
-	if endpoint := os.Getenv("S3_ENDPOINT"); endpoint != "" {
+	if endpoint := getenv("S3_ENDPOINT"); endpoint != "" {
 		c.S3.Endpoint = endpoint
 	}
 
 	// Logger
-	if level := os.Getenv("LOG_LEVEL"); level != "" {
+	if level := getenv("LOG_LEVEL"); level != "" {
 		c.Logger.Level = level
 	}
+
+	// Rate limit - RATE_LIMIT_ENABLED takes precedence over the older ENABLE_RATE_LIMIT
+	enabled := getenv("RATE_LIMIT_ENABLED")
+	if enabled == "" {
+		enabled = getenv("ENABLE_RATE_LIMIT")
+	}
+	if enabled != "" {
+		if b, err := strconv.ParseBool(enabled); err == nil {
+			c.RateLimit.Enabled = b
+		}
+	}
+	if rps := getenv("RATE_LIMIT_RPS"); rps != "" {
+		if r, err := strconv.ParseFloat(rps, 64); err == nil {
+			c.RateLimit.RequestsPerSecond = r
+		}
+	}
+	if burst := getenv("RATE_LIMIT_BURST"); burst != "" {
+		if b, err := strconv.Atoi(burst); err == nil {
+			c.RateLimit.Burst = b
+		}
+	}
+	if failClosed := getenv("RATE_LIMIT_FAIL_CLOSED"); failClosed != "" {
+		if b, err := strconv.ParseBool(failClosed); err == nil {
+			c.RateLimit.FailClosed = b
+		}
+	}
+	if cidrs := getenv("RATE_LIMIT_EXEMPT_CIDRS"); cidrs != "" {
+		c.RateLimit.ExemptCIDRs = splitList(cidrs)
+	}
+	if paths := getenv("RATE_LIMIT_EXEMPT_PATHS"); paths != "" {
+		c.RateLimit.ExemptPaths = splitList(paths)
+	}
+	if keys := getenv("RATE_LIMIT_EXEMPT_API_KEYS"); keys != "" {
+		c.RateLimit.ExemptAPIKeys = splitList(keys)
+	}
+	if header := getenv("RATE_LIMIT_API_KEY_HEADER"); header != "" {
+		c.RateLimit.APIKeyHeader = header
+	}
+
+	// Worker
+	if count := getenv("WORKER_COUNT"); count != "" {
+		if n, err := strconv.Atoi(count); err == nil {
+			c.Worker.Count = n
+		}
+	}
+	if size := getenv("WORKER_QUEUE_SIZE"); size != "" {
+		if n, err := strconv.Atoi(size); err == nil {
+			c.Worker.QueueSize = n
+		}
+	}
+	if interval := getenv("WORKER_POLL_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil {
+			c.Worker.PollInterval = d
+		}
+	}
+
+	// Middleware
+	if enabled := getenv("ENABLE_METRICS"); enabled != "" {
+		if b, err := strconv.ParseBool(enabled); err == nil {
+			c.Middleware.EnableMetrics = b
+		}
+	}
+	if enabled := getenv("ENABLE_CORS"); enabled != "" {
+		if b, err := strconv.ParseBool(enabled); err == nil {
+			c.Middleware.EnableCORS = b
+		}
+	}
+	if enabled := getenv("ENABLE_TRACING"); enabled != "" {
+		if b, err := strconv.ParseBool(enabled); err == nil {
+			c.Middleware.EnableTracing = b
+		}
+	}
+
+	// Tracing
+	if name := getenv("OTEL_SERVICE_NAME"); name != "" {
+		c.Tracing.ServiceName = name
+	}
+	if ratio := getenv("TRACING_SAMPLE_RATIO"); ratio != "" {
+		if r, err := strconv.ParseFloat(ratio, 64); err == nil {
+			c.Tracing.SampleRatio = r
+		}
+	}
+	// TRACING_ROUTE_SAMPLE_RATIOS format: /route=ratio,/route=ratio
+	if ratios := getenv("TRACING_ROUTE_SAMPLE_RATIOS"); ratios != "" {
+		c.Tracing.RouteSampleRatios = make(map[string]float64)
+		for _, pair := range splitList(ratios) {
+			route, ratio, ok := strings.Cut(pair, "=")
+			if !ok {
+				continue
+			}
+			if r, err := strconv.ParseFloat(strings.TrimSpace(ratio), 64); err == nil {
+				c.Tracing.RouteSampleRatios[strings.TrimSpace(route)] = r
+			}
+		}
+	}
 }
 
 // parseDatabaseURL parses DATABASE_URL and populates individual fields
@@ -319,6 +588,47 @@ func (c *Config) parseDatabaseURL(databaseURL string) {
 	}
 }
 
+// parseRedisURL parses REDIS_URL (redis:// or rediss://) and populates individual fields
+func (c *Config) parseRedisURL(redisURL string) {
+	u, err := url.Parse(redisURL)
+	if err != nil {
+		return
+	}
+
+	switch u.Scheme {
+	case "redis":
+		c.Redis.TLS = false
+	case "rediss":
+		c.Redis.TLS = true
+	default:
+		return
+	}
+
+	if u.User != nil {
+		c.Redis.Username = u.User.Username()
+		if password, ok := u.User.Password(); ok {
+			c.Redis.Password = password
+		}
+	}
+
+	if host := u.Hostname(); host != "" {
+		c.Redis.Host = host
+	}
+	if port := u.Port(); port != "" {
+		if p, err := strconv.Atoi(port); err == nil {
+			c.Redis.Port = p
+		}
+	} else {
+		c.Redis.Port = 6379
+	}
+
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		if n, err := strconv.Atoi(db); err == nil {
+			c.Redis.DB = n
+		}
+	}
+}
+
 // GetDSN returns the database connection string
 func (c *DatabaseConfig) GetDSN() string {
 	if c.URL != "" {
@@ -328,13 +638,61 @@ func (c *DatabaseConfig) GetDSN() string {
 	if sslmode == "" {
 		sslmode = "disable"
 	}
-	return fmt.Sprintf(
+	dsn := fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		c.Host, c.Port, c.User, c.Password, c.DBName, sslmode,
 	)
+	// Server-side ceiling so statements stop even if the client goes away;
+	// dbutil applies the tighter per-operation deadlines
+	if timeout := max(c.ReadTimeout, c.WriteTimeout); timeout > 0 {
+		dsn += fmt.Sprintf(" statement_timeout=%d", timeout.Milliseconds())
+	}
+	return dsn
+}
+
+// EffectiveExpiry returns ExpireTime, or the 24h default when it is not positive
+func (c *JWTConfig) EffectiveExpiry() time.Duration {
+	if c.ExpireTime <= 0 {
+		return defaultJWTExpireTime
+	}
+	return c.ExpireTime
+}
+
+// SigningSecret returns the secret new tokens are signed with: Secret, or the
+// first of Secrets when Secret is unset
+func (c *JWTConfig) SigningSecret() string {
+	if c.Secret == "" && len(c.Secrets) > 0 {
+		return c.Secrets[0]
+	}
+	return c.Secret
+}
+
+// VerificationSecrets returns every secret a token may be signed with, current
+// first, so tokens issued before a rotation stay valid
+func (c *JWTConfig) VerificationSecrets() []string {
+	secrets := make([]string, 0, len(c.Secrets)+1)
+	seen := make(map[string]bool, len(c.Secrets)+1)
+	for _, secret := range append([]string{c.Secret}, c.Secrets...) {
+		if secret != "" && !seen[secret] {
+			seen[secret] = true
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets
 }
 
 // GetRedisAddr returns Redis address in host:port format
 func (c *RedisConfig) GetRedisAddr() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
+
+// splitList splits a comma-separated env value, trimming spaces and dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,277 @@
+package config
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRateLimitDisabledByDefault(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.RateLimit.Enabled {
+		t.Fatal("rate limiting should be disabled by default")
+	}
+}
+
+func TestLoadFromEnvRateLimit(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "true")
+	t.Setenv("RATE_LIMIT_RPS", "2.5")
+	t.Setenv("RATE_LIMIT_BURST", "7")
+
+	cfg := DefaultConfig()
+	cfg.LoadFromEnv()
+
+	if !cfg.RateLimit.Enabled {
+		t.Error("Enabled = false, want true")
+	}
+	if cfg.RateLimit.RequestsPerSecond != 2.5 {
+		t.Errorf("RequestsPerSecond = %v, want 2.5", cfg.RateLimit.RequestsPerSecond)
+	}
+	if cfg.RateLimit.Burst != 7 {
+		t.Errorf("Burst = %d, want 7", cfg.RateLimit.Burst)
+	}
+}
+
+func TestLoadFromEnvRateLimitIgnoresInvalidValues(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "maybe")
+	t.Setenv("RATE_LIMIT_RPS", "fast")
+	t.Setenv("RATE_LIMIT_BURST", "lots")
+
+	cfg := DefaultConfig()
+	cfg.LoadFromEnv()
+
+	want := DefaultConfig().RateLimit
+	if cfg.RateLimit.Enabled != want.Enabled || cfg.RateLimit.RequestsPerSecond != want.RequestsPerSecond || cfg.RateLimit.Burst != want.Burst {
+		t.Errorf("RateLimit = %+v, want defaults %+v", cfg.RateLimit, want)
+	}
+}
+
+func TestLoadFromEnvRateLimitEnabledAlias(t *testing.T) {
+	tests := []struct {
+		name   string
+		modern string
+		legacy string
+		want   bool
+	}{
+		{"legacy only", "", "true", true},
+		{"modern wins over legacy", "false", "true", false},
+		{"modern only", "true", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RATE_LIMIT_ENABLED", tt.modern)
+			t.Setenv("ENABLE_RATE_LIMIT", tt.legacy)
+
+			cfg := DefaultConfig()
+			cfg.LoadFromEnv()
+			if cfg.RateLimit.Enabled != tt.want {
+				t.Errorf("RateLimit.Enabled = %v, want %v", cfg.RateLimit.Enabled, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadLegacyRateLimitYAMLKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"legacy key only", "middleware:\n  enable_rate_limit: true\n", true},
+		{"rate_limit.enabled wins", "middleware:\n  enable_rate_limit: true\nrate_limit:\n  enabled: false\n", false},
+		{"rate_limit.enabled only", "rate_limit:\n  enabled: true\n", true},
+		{"neither", "middleware:\n  enable_cors: true\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RATE_LIMIT_ENABLED", "")
+			t.Setenv("ENABLE_RATE_LIMIT", "")
+			cfg, err := Load(writeConfig(t, t.TempDir(), tt.content))
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.RateLimit.Enabled != tt.want {
+				t.Errorf("RateLimit.Enabled = %v, want %v", cfg.RateLimit.Enabled, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadRateLimitEnvOverridesLegacyYAMLKey(t *testing.T) {
+	t.Setenv("ENABLE_RATE_LIMIT", "")
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+	cfg, err := Load(writeConfig(t, t.TempDir(), "middleware:\n  enable_rate_limit: true\n"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.RateLimit.Enabled {
+		t.Error("RateLimit.Enabled = true, want RATE_LIMIT_ENABLED=false to win over the file")
+	}
+}
+
+func TestLoadFromEnvRateLimitAPIKeyHeader(t *testing.T) {
+	t.Setenv("RATE_LIMIT_API_KEY_HEADER", "X-Service-Key")
+
+	cfg := DefaultConfig()
+	cfg.LoadFromEnv()
+	if cfg.RateLimit.APIKeyHeader != "X-Service-Key" {
+		t.Errorf("APIKeyHeader = %q, want X-Service-Key", cfg.RateLimit.APIKeyHeader)
+	}
+}
+
+func TestServicesPropagateHeaders(t *testing.T) {
+	t.Setenv("SERVICES_PROPAGATE_HEADERS", "")
+	cfg := DefaultConfig()
+	cfg.LoadFromEnv()
+	if !slices.Contains(cfg.Services.PropagateHeaders, "X-B3-Traceid") {
+		t.Errorf("default PropagateHeaders = %v, want the B3 headers", cfg.Services.PropagateHeaders)
+	}
+
+	t.Setenv("SERVICES_PROPAGATE_HEADERS", "x-request-id, x-tenant")
+	cfg = DefaultConfig()
+	cfg.LoadFromEnv()
+	if want := []string{"x-request-id", "x-tenant"}; !slices.Equal(cfg.Services.PropagateHeaders, want) {
+		t.Errorf("PropagateHeaders = %v, want %v", cfg.Services.PropagateHeaders, want)
+	}
+}
+
+func TestLoadFromEnvJWTExpiry(t *testing.T) {
+	t.Setenv("JWT_EXPIRE_TIME", "2h")
+	t.Setenv("JWT_MAX_EXPIRE_TIME", "168h")
+
+	cfg := DefaultConfig()
+	cfg.LoadFromEnv()
+	if cfg.JWT.ExpireTime != 2*time.Hour || cfg.JWT.MaxExpireTime != 168*time.Hour {
+		t.Errorf("ExpireTime %s, MaxExpireTime %s, want 2h and 168h", cfg.JWT.ExpireTime, cfg.JWT.MaxExpireTime)
+	}
+}
+
+func TestLoadFromEnvRateLimitExemptions(t *testing.T) {
+	t.Setenv("RATE_LIMIT_EXEMPT_CIDRS", "10.0.0.0/8, 192.0.2.1")
+	t.Setenv("RATE_LIMIT_EXEMPT_PATHS", "/health,/metrics")
+	t.Setenv("RATE_LIMIT_EXEMPT_API_KEYS", "internal-key")
+
+	cfg := DefaultConfig()
+	cfg.LoadFromEnv()
+	if want := []string{"10.0.0.0/8", "192.0.2.1"}; !slices.Equal(cfg.RateLimit.ExemptCIDRs, want) {
+		t.Errorf("ExemptCIDRs = %v, want %v", cfg.RateLimit.ExemptCIDRs, want)
+	}
+	if want := []string{"/health", "/metrics"}; !slices.Equal(cfg.RateLimit.ExemptPaths, want) {
+		t.Errorf("ExemptPaths = %v, want %v", cfg.RateLimit.ExemptPaths, want)
+	}
+	if want := []string{"internal-key"}; !slices.Equal(cfg.RateLimit.ExemptAPIKeys, want) {
+		t.Errorf("ExemptAPIKeys = %v, want %v", cfg.RateLimit.ExemptAPIKeys, want)
+	}
+}
+
+func TestWorkerCountDefaultsToNumCPU(t *testing.T) {
+	t.Setenv("WORKER_COUNT", "")
+
+	cfg := DefaultConfig()
+	cfg.LoadFromEnv()
+
+	if cfg.Worker.Count != runtime.NumCPU() {
+		t.Errorf("Count = %d, want runtime.NumCPU() = %d", cfg.Worker.Count, runtime.NumCPU())
+	}
+}
+
+func TestLoadFromEnvWorker(t *testing.T) {
+	t.Setenv("WORKER_COUNT", "3")
+	t.Setenv("WORKER_QUEUE_SIZE", "250")
+	t.Setenv("WORKER_POLL_INTERVAL", "250ms")
+
+	cfg := DefaultConfig()
+	cfg.LoadFromEnv()
+
+	if cfg.Worker.Count != 3 {
+		t.Errorf("Count = %d, want 3", cfg.Worker.Count)
+	}
+	if cfg.Worker.QueueSize != 250 {
+		t.Errorf("QueueSize = %d, want 250", cfg.Worker.QueueSize)
+	}
+	if cfg.Worker.PollInterval != 250*time.Millisecond {
+		t.Errorf("PollInterval = %s, want 250ms", cfg.Worker.PollInterval)
+	}
+}
+
+func TestLoadFromEnvMiddlewareToggles(t *testing.T) {
+	t.Setenv("ENABLE_METRICS", "false")
+	t.Setenv("ENABLE_CORS", "false")
+	t.Setenv("ENABLE_TRACING", "true")
+
+	cfg := DefaultConfig()
+	if !cfg.Middleware.EnableMetrics || !cfg.Middleware.EnableCORS || cfg.Middleware.EnableTracing {
+		t.Fatalf("defaults = %+v, want metrics and CORS on, tracing off", cfg.Middleware)
+	}
+
+	cfg.LoadFromEnv()
+	if cfg.Middleware.EnableMetrics || cfg.Middleware.EnableCORS || !cfg.Middleware.EnableTracing {
+		t.Errorf("Middleware = %+v, want metrics and CORS off, tracing on", cfg.Middleware)
+	}
+}
+
+func TestJWTSecretsFromEnvAndYAML(t *testing.T) {
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("SECRET_KEY", "")
+	t.Setenv("JWT_SECRETS", "current, previous")
+
+	cfg := DefaultConfig()
+	cfg.LoadFromEnv()
+	if got := cfg.JWT.VerificationSecrets(); len(got) != 2 || got[0] != "current" || got[1] != "previous" {
+		t.Errorf("VerificationSecrets() = %v, want [current previous]", got)
+	}
+	if got := cfg.JWT.SigningSecret(); got != "current" {
+		t.Errorf("SigningSecret() = %q, want current", got)
+	}
+
+	t.Setenv("JWT_SECRETS", "")
+	path := writeConfig(t, t.TempDir(), "jwt:\n  secret: current\n  secrets:\n    - previous\n    - current\n")
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := loaded.JWT.VerificationSecrets(); len(got) != 2 || got[0] != "current" || got[1] != "previous" {
+		t.Errorf("VerificationSecrets() = %v, want [current previous] without duplicates", got)
+	}
+}
+
+func TestDatabaseTimeoutsFromEnv(t *testing.T) {
+	t.Setenv("DB_READ_TIMEOUT", "5s")
+	t.Setenv("DB_WRITE_TIMEOUT", "2s")
+
+	cfg := DefaultConfig()
+	cfg.LoadFromEnv()
+	if cfg.Database.ReadTimeout != 5*time.Second || cfg.Database.WriteTimeout != 2*time.Second {
+		t.Errorf("timeouts = %s read, %s write, want 5s and 2s", cfg.Database.ReadTimeout, cfg.Database.WriteTimeout)
+	}
+
+	t.Setenv("DB_READ_TIMEOUT", "soon")
+	cfg = DefaultConfig()
+	cfg.LoadFromEnv()
+	if cfg.Database.ReadTimeout != DefaultConfig().Database.ReadTimeout {
+		t.Errorf("invalid DB_READ_TIMEOUT changed the timeout to %s", cfg.Database.ReadTimeout)
+	}
+}
+
+func TestGetDSNStatementTimeout(t *testing.T) {
+	tests := []struct {
+		name  string
+		read  time.Duration
+		write time.Duration
+		want  string
+	}{
+		{"larger of read and write", 5 * time.Second, 2 * time.Second, " statement_timeout=5000"},
+		{"write larger", time.Second, 1500 * time.Millisecond, " statement_timeout=1500"},
+		{"no timeouts", 0, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := DatabaseConfig{Host: "db", Port: "5432", User: "app", Password: "pw", DBName: "app", ReadTimeout: tt.read, WriteTimeout: tt.write}
+			want := "host=db port=5432 user=app password=pw dbname=app sslmode=disable" + tt.want
+			if got := db.GetDSN(); got != want {
+				t.Errorf("GetDSN() = %q, want %q", got, want)
+			}
+		})
+	}
+}
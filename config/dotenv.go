@@ -0,0 +1,108 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadWithDotenv loads a .env file into the process environment and then
+// loads configuration like Load. Variables already set in the environment take
+// precedence over the .env file. A missing env file is ignored.
+func LoadWithDotenv(configPath, envPath string) (*Config, error) {
+	if envPath != "" {
+		if err := loadDotenv(envPath); err != nil {
+			return nil, err
+		}
+	}
+	return Load(configPath)
+}
+
+// loadDotenv sets the variables from the env file that are not already set
+func loadDotenv(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to open env file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		key, value, ok, err := parseDotenvLine(scanner.Text())
+		if err != nil {
+			return fmt.Errorf("failed to parse env file %s line %d: %w", path, lineNum, err)
+		}
+		if !ok {
+			continue
+		}
+		if _, exists := os.LookupEnv(key); exists {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to set %s from env file: %w", key, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read env file: %w", err)
+	}
+	return nil
+}
+
+// parseDotenvLine parses a KEY=value line. ok is false for blank and comment lines.
+func parseDotenvLine(line string) (key, value string, ok bool, err error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", false, nil
+	}
+	line = strings.TrimPrefix(line, "export ")
+
+	key, value, found := strings.Cut(line, "=")
+	if !found {
+		return "", "", false, errors.New("expected KEY=value")
+	}
+	key = strings.TrimSpace(key)
+	if !validEnvKey(key) {
+		return "", "", false, fmt.Errorf("invalid variable name %q", key)
+	}
+
+	value = strings.TrimSpace(value)
+	switch {
+	case strings.HasPrefix(value, `"`):
+		if len(value) < 2 || !strings.HasSuffix(value, `"`) {
+			return "", "", false, errors.New("unterminated double-quoted value")
+		}
+		value = strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`).Replace(value[1 : len(value)-1])
+	case strings.HasPrefix(value, "'"):
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return "", "", false, errors.New("unterminated single-quoted value")
+		}
+		value = value[1 : len(value)-1]
+	default:
+		// Unquoted values may carry a trailing comment
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+	}
+	return key, value, true, nil
+}
+
+// validEnvKey reports whether key is a valid environment variable name
+func validEnvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
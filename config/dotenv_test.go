@@ -0,0 +1,98 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// unsetEnv unsets names for the test, restoring their values afterwards
+func unsetEnv(t *testing.T, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+}
+
+// writeDotenv writes content to a .env file in a temp dir and returns its path
+func writeDotenv(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write env file: %v", err)
+	}
+	return path
+}
+
+func TestLoadWithDotenv(t *testing.T) {
+	unsetEnv(t, "PORT", "SERVER_PORT", "DB_HOST", "JWT_SECRET", "DOTENV_TEST_QUOTED", "DOTENV_TEST_SINGLE", "DOTENV_TEST_COMMENT")
+	path := writeDotenv(t, `
+# local development
+export SERVER_PORT=9090
+DB_HOST=db.local
+JWT_SECRET="s3cr3t with spaces"
+DOTENV_TEST_QUOTED="line1\nline2 \"quoted\""
+DOTENV_TEST_SINGLE='raw \n value'
+DOTENV_TEST_COMMENT=value # trailing comment
+`)
+
+	cfg, err := LoadWithDotenv("", path)
+	if err != nil {
+		t.Fatalf("LoadWithDotenv: %v", err)
+	}
+	if cfg.Server.Port != 9090 || cfg.Database.Host != "db.local" || cfg.JWT.Secret != "s3cr3t with spaces" {
+		t.Errorf("config = port %d, host %q, secret %q, want values from the env file",
+			cfg.Server.Port, cfg.Database.Host, cfg.JWT.Secret)
+	}
+
+	want := map[string]string{
+		"DOTENV_TEST_QUOTED":  "line1\nline2 \"quoted\"",
+		"DOTENV_TEST_SINGLE":  `raw \n value`,
+		"DOTENV_TEST_COMMENT": "value",
+	}
+	for name, value := range want {
+		if got := os.Getenv(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestLoadWithDotenvEnvironmentTakesPrecedence(t *testing.T) {
+	unsetEnv(t, "PORT")
+	t.Setenv("SERVER_PORT", "7070")
+	path := writeDotenv(t, "SERVER_PORT=9090\n")
+
+	cfg, err := LoadWithDotenv("", path)
+	if err != nil {
+		t.Fatalf("LoadWithDotenv: %v", err)
+	}
+	if cfg.Server.Port != 7070 {
+		t.Errorf("port = %d, want the real environment's 7070", cfg.Server.Port)
+	}
+}
+
+func TestLoadWithDotenvMissingFileIgnored(t *testing.T) {
+	if _, err := LoadWithDotenv("", filepath.Join(t.TempDir(), "missing.env")); err != nil {
+		t.Fatalf("LoadWithDotenv with a missing file: %v", err)
+	}
+}
+
+func TestLoadWithDotenvMalformed(t *testing.T) {
+	tests := map[string]string{
+		"no equals":     "JUST_A_WORD\n",
+		"invalid name":  "1BAD=value\n",
+		"unterminated":  "KEY=\"open\n",
+		"single quoted": "KEY='open\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			unsetEnv(t, "KEY", "JUST_A_WORD")
+			_, err := LoadWithDotenv("", writeDotenv(t, "# header\n"+content))
+			if err == nil || !strings.Contains(err.Error(), "line 2") {
+				t.Fatalf("LoadWithDotenv() = %v, want a parse error on line 2", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"net/url"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redactedValue replaces secrets in redacted output
+const redactedValue = "***"
+
+// Redacted returns a deep copy of the config with secrets replaced by "***".
+// Unset secrets stay empty so it remains visible whether they are configured.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+
+	// Copy every map and slice so changing the copy never reaches c
+	redacted.FeatureRollout = maps.Clone(c.FeatureRollout)
+	redacted.Tracing.RouteSampleRatios = maps.Clone(c.Tracing.RouteSampleRatios)
+	redacted.Services.PropagateHeaders = slices.Clone(c.Services.PropagateHeaders)
+	redacted.RateLimit.ExemptCIDRs = slices.Clone(c.RateLimit.ExemptCIDRs)
+	redacted.RateLimit.ExemptPaths = slices.Clone(c.RateLimit.ExemptPaths)
+
+	redacted.Database.Password = redact(c.Database.Password)
+	redacted.Database.URL = redactURL(c.Database.URL)
+	redacted.Redis.Password = redact(c.Redis.Password)
+	redacted.Redis.URL = redactURL(c.Redis.URL)
+	redacted.JWT.Secret = redact(c.JWT.Secret)
+	redacted.JWT.Secrets = make([]string, len(c.JWT.Secrets))
+	for i, secret := range c.JWT.Secrets {
+		redacted.JWT.Secrets[i] = redact(secret)
+	}
+	redacted.S3.SecretKey = redact(c.S3.SecretKey)
+	redacted.RateLimit.ExemptAPIKeys = make([]string, len(c.RateLimit.ExemptAPIKeys))
+	for i, key := range c.RateLimit.ExemptAPIKeys {
+		redacted.RateLimit.ExemptAPIKeys[i] = redact(key)
+	}
+
+	return &redacted
+}
+
+// String returns the redacted config as YAML, so printing it with %v or %+v
+// never exposes secrets
+func (c Config) String() string {
+	data, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return "config: " + err.Error()
+	}
+	return string(data)
+}
+
+// MarshalJSON encodes the database config with the password masked
+func (c DatabaseConfig) MarshalJSON() ([]byte, error) {
+	type plain DatabaseConfig
+	c.Password = redact(c.Password)
+	c.URL = redactURL(c.URL)
+	return json.Marshal(plain(c))
+}
+
+// MarshalJSON encodes the Redis config with the password masked
+func (c RedisConfig) MarshalJSON() ([]byte, error) {
+	type plain RedisConfig
+	c.Password = redact(c.Password)
+	c.URL = redactURL(c.URL)
+	return json.Marshal(plain(c))
+}
+
+// MarshalJSON encodes the JWT config with the secrets masked
+func (c JWTConfig) MarshalJSON() ([]byte, error) {
+	type plain JWTConfig
+	c.Secret = redact(c.Secret)
+	secrets := make([]string, len(c.Secrets))
+	for i, secret := range c.Secrets {
+		secrets[i] = redact(secret)
+	}
+	c.Secrets = secrets
+	return json.Marshal(plain(c))
+}
+
+// MarshalJSON encodes the S3 config with the secret key masked
+func (c S3Config) MarshalJSON() ([]byte, error) {
+	type plain S3Config
+	c.SecretKey = redact(c.SecretKey)
+	return json.Marshal(plain(c))
+}
+
+// MarshalJSON encodes the rate limit config with the exempt API keys masked
+func (c RateLimitConfig) MarshalJSON() ([]byte, error) {
+	type plain RateLimitConfig
+	keys := make([]string, len(c.ExemptAPIKeys))
+	for i, key := range c.ExemptAPIKeys {
+		keys[i] = redact(key)
+	}
+	c.ExemptAPIKeys = keys
+	return json.Marshal(plain(c))
+}
+
+// Hash returns a sha256 hex digest of the redacted config, so it changes when
+// effective configuration changes but not when a secret is rotated
+func (c *Config) Hash() string {
+	data, err := json.Marshal(c.Redacted())
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// redact masks a non-empty secret
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// redactURL masks the password in a connection URL
+func redactURL(rawURL string) string {
+	if rawURL == "" {
+		return ""
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return redactedValue
+	}
+	return u.Redacted()
+}
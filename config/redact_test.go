@@ -0,0 +1,120 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestHashStable(t *testing.T) {
+	cfg := validConfig()
+	cfg.FeatureRollout = map[string]int{"new-board": 10, "dark-mode": 50, "chat-v2": 100}
+
+	first := cfg.Hash()
+	if len(first) != 64 {
+		t.Fatalf("Hash() = %q, want a sha256 hex digest", first)
+	}
+	for i := 0; i < 10; i++ {
+		if got := cfg.Hash(); got != first {
+			t.Fatalf("Hash() changed between calls: %q != %q", got, first)
+		}
+	}
+
+	same := validConfig()
+	same.FeatureRollout = map[string]int{"chat-v2": 100, "dark-mode": 50, "new-board": 10}
+	if got := same.Hash(); got != first {
+		t.Errorf("equal configs hash differently: %q != %q", got, first)
+	}
+}
+
+func TestHashChangesWithNonSecretField(t *testing.T) {
+	cfg := validConfig()
+	before := cfg.Hash()
+
+	cfg.Server.Port = 9090
+	if cfg.Hash() == before {
+		t.Error("hash unchanged after changing server.port")
+	}
+}
+
+func TestHashIgnoresSecretRotation(t *testing.T) {
+	cfg := validConfig()
+	cfg.JWT.Secret = "old-secret"
+	cfg.Database.Password = "old-password"
+	cfg.S3.SecretKey = "old-key"
+	before := cfg.Hash()
+
+	cfg.JWT.Secret = "new-secret"
+	cfg.Database.Password = "new-password"
+	cfg.S3.SecretKey = "new-key"
+	if got := cfg.Hash(); got != before {
+		t.Error("hash changed when only secrets were rotated")
+	}
+}
+
+func TestRedactedMasksExemptAPIKeys(t *testing.T) {
+	cfg := validConfig()
+	cfg.RateLimit.ExemptAPIKeys = []string{"internal-key"}
+
+	redacted := cfg.Redacted()
+	if len(redacted.RateLimit.ExemptAPIKeys) != 1 || redacted.RateLimit.ExemptAPIKeys[0] == "internal-key" {
+		t.Errorf("Redacted() exempt API keys = %v, want one masked key", redacted.RateLimit.ExemptAPIKeys)
+	}
+	if cfg.RateLimit.ExemptAPIKeys[0] != "internal-key" {
+		t.Error("Redacted() modified the original config")
+	}
+
+	data, err := json.Marshal(cfg.RateLimit)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if strings.Contains(string(data), "internal-key") {
+		t.Errorf("JSON %s contains the exempt API key", data)
+	}
+}
+
+func TestHashIgnoresExemptAPIKeyRotation(t *testing.T) {
+	cfg := validConfig()
+	cfg.RateLimit.ExemptAPIKeys = []string{"old-key"}
+	before := cfg.Hash()
+
+	cfg.RateLimit.ExemptAPIKeys = []string{"new-key"}
+	if cfg.Hash() != before {
+		t.Error("hash changed when only an exempt API key was rotated")
+	}
+}
+
+func TestRedactedIsDeepCopy(t *testing.T) {
+	cfg := validConfig()
+	cfg.FeatureRollout = map[string]int{"new-board": 10}
+	cfg.Tracing.RouteSampleRatios = map[string]float64{"/health": 0}
+	cfg.Services.PropagateHeaders = []string{"X-Request-Id"}
+	cfg.JWT.Secrets = []string{"previous"}
+	cfg.RateLimit.ExemptCIDRs = []string{"10.0.0.0/8"}
+	cfg.RateLimit.ExemptPaths = []string{"/health"}
+	cfg.RateLimit.ExemptAPIKeys = []string{"internal-key"}
+	before := cfg.Hash()
+
+	redacted := cfg.Redacted()
+	redacted.FeatureRollout["new-board"] = 100
+	redacted.Tracing.RouteSampleRatios["/health"] = 1
+	redacted.Services.PropagateHeaders[0] = "Cookie"
+	redacted.JWT.Secrets[0] = "changed"
+	redacted.RateLimit.ExemptCIDRs[0] = "0.0.0.0/0"
+	redacted.RateLimit.ExemptPaths[0] = "/"
+	redacted.RateLimit.ExemptAPIKeys[0] = "changed"
+
+	switch {
+	case cfg.FeatureRollout["new-board"] != 10,
+		cfg.Tracing.RouteSampleRatios["/health"] != 0,
+		cfg.Services.PropagateHeaders[0] != "X-Request-Id",
+		cfg.JWT.Secrets[0] != "previous",
+		cfg.RateLimit.ExemptCIDRs[0] != "10.0.0.0/8",
+		cfg.RateLimit.ExemptPaths[0] != "/health",
+		cfg.RateLimit.ExemptAPIKeys[0] != "internal-key":
+		t.Errorf("changing the redacted copy changed the original: %+v", cfg)
+	}
+	if cfg.Hash() != before {
+		t.Error("original config hash changed after mutating the redacted copy")
+	}
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// ReloadOnSignal reloads the config at path whenever the process receives SIGHUP
+// and passes the fresh config to onReload. Reload errors, including a missing or
+// unreadable file, are logged and the previous configuration stays in effect.
+// The returned function stops listening.
+func ReloadOnSignal(path string, onReload func(*Config)) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				if err := reload(path, onReload); err != nil {
+					log.Printf("config: reload of %q failed: %v", path, err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
+
+// reload loads the config at path and hands it to onReload. onReload is not
+// called when loading fails.
+func reload(path string, onReload func(*Config)) error {
+	cfg, err := loadFile(path)
+	if err != nil {
+		return err
+	}
+	onReload(cfg)
+	return nil
+}
+
+// loadFile loads configuration like Load, except that a file that cannot be
+// read is an error rather than silently falling back to the defaults
+func loadFile(path string) (*Config, error) {
+	if path == "" {
+		return Load(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	cfg := DefaultConfig()
+	if err := parseYAML(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	cfg.LoadFromEnv()
+	return cfg, nil
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeConfig writes a YAML config file into dir and returns its path
+func writeConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func TestReloadAppliesChangedFile(t *testing.T) {
+	t.Setenv("PORT", "")
+	t.Setenv("SERVER_PORT", "")
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "server:\n  port: 9090\n")
+	writeConfig(t, dir, "server:\n  port: 9191\n")
+
+	var got *Config
+	if err := reload(path, func(cfg *Config) { got = cfg }); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if got == nil || got.Server.Port != 9191 {
+		t.Fatalf("reloaded config = %+v, want port 9191", got)
+	}
+}
+
+func TestReloadFailsWithoutCallingOnReload(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"missing file": filepath.Join(dir, "missing.yaml"),
+		"invalid yaml": writeConfig(t, dir, "server: [unclosed\n"),
+	}
+	for name, path := range tests {
+		t.Run(name, func(t *testing.T) {
+			called := false
+			if err := reload(path, func(*Config) { called = true }); err == nil {
+				t.Error("reload returned nil error")
+			}
+			if called {
+				t.Error("onReload called for a failed reload")
+			}
+		})
+	}
+}
+
+func TestReloadOnSignalStop(t *testing.T) {
+	stop := ReloadOnSignal("", func(*Config) {})
+	stop()
+	stop() // safe to call twice
+}
@@ -0,0 +1,33 @@
+//go:build linux || darwin || freebsd
+
+package config
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReloadOnSignal(t *testing.T) {
+	t.Setenv("PORT", "")
+	t.Setenv("SERVER_PORT", "")
+	path := writeConfig(t, t.TempDir(), "server:\n  port: 9090\n")
+
+	reloaded := make(chan *Config, 1)
+	stop := ReloadOnSignal(path, func(cfg *Config) { reloaded <- cfg })
+	defer stop()
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatalf("send SIGHUP: %v", err)
+	}
+
+	select {
+	case cfg := <-reloaded:
+		if cfg.Server.Port != 9090 {
+			t.Errorf("reloaded port = %d, want 9090", cfg.Server.Port)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("onReload not called after SIGHUP")
+	}
+}
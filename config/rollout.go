@@ -0,0 +1,21 @@
+package config
+
+import "hash/fnv"
+
+// IsEnabledFor reports whether feature is enabled for key (e.g. a user ID)
+// under the FeatureRollout percentage. Keys are bucketed deterministically per
+// feature, so a key keeps its answer as long as the percentage does not shrink
+// and different features roll out to different subsets. Unknown features are disabled.
+func (c *Config) IsEnabledFor(feature, key string) bool {
+	percent, ok := c.FeatureRollout[feature]
+	if !ok || percent <= 0 {
+		return false
+	}
+	if percent >= 100 {
+		return true
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(feature + ":" + key))
+	return int(hash.Sum32()%100) < percent
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestIsEnabledForIsStable(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.FeatureRollout = map[string]int{"new-board": 30}
+
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("user-%d", i)
+		first := cfg.IsEnabledFor("new-board", key)
+		for j := 0; j < 3; j++ {
+			if cfg.IsEnabledFor("new-board", key) != first {
+				t.Fatalf("IsEnabledFor(%q) changed between calls", key)
+			}
+		}
+	}
+}
+
+func TestIsEnabledForMatchesPercentage(t *testing.T) {
+	const keys = 10000
+	for _, percent := range []int{1, 10, 30, 50, 90} {
+		cfg := DefaultConfig()
+		cfg.FeatureRollout = map[string]int{"new-board": percent}
+
+		enabled := 0
+		for i := 0; i < keys; i++ {
+			if cfg.IsEnabledFor("new-board", fmt.Sprintf("user-%d", i)) {
+				enabled++
+			}
+		}
+		got := float64(enabled) * 100 / keys
+		if diff := got - float64(percent); diff < -2 || diff > 2 {
+			t.Errorf("%d%% rollout enabled %.1f%% of keys", percent, got)
+		}
+	}
+}
+
+func TestIsEnabledForBounds(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.FeatureRollout = map[string]int{"off": 0, "negative": -5, "on": 100, "over": 150}
+
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("user-%d", i)
+		if cfg.IsEnabledFor("off", key) || cfg.IsEnabledFor("negative", key) || cfg.IsEnabledFor("unknown", key) {
+			t.Fatalf("%s enabled for a 0%%, negative or unknown rollout", key)
+		}
+		if !cfg.IsEnabledFor("on", key) || !cfg.IsEnabledFor("over", key) {
+			t.Fatalf("%s not enabled for a full rollout", key)
+		}
+	}
+}
+
+func TestIsEnabledForBucketsPerFeature(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.FeatureRollout = map[string]int{"a": 50, "b": 50}
+
+	differ := 0
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("user-%d", i)
+		if cfg.IsEnabledFor("a", key) != cfg.IsEnabledFor("b", key) {
+			differ++
+		}
+	}
+	if differ < 300 {
+		t.Errorf("features disagree for %d of 1000 keys, want independent bucketing", differ)
+	}
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// defaultMinIORegion is the region MinIO accepts when none is configured
+const defaultMinIORegion = "us-east-1"
+
+// Validate checks the configuration for inconsistencies and returns all
+// problems found joined into a single error
+func (c *Config) Validate() error {
+	var errs []error
+
+	if !validPort(c.Server.Port) {
+		errs = append(errs, fmt.Errorf("server.port must be between 1 and 65535, got %d", c.Server.Port))
+	}
+
+	if c.Database.URL == "" {
+		if c.Database.Host == "" || c.Database.DBName == "" {
+			errs = append(errs, errors.New("database requires either url or host and dbname"))
+		}
+		if c.Database.Port != "" {
+			if port, err := strconv.Atoi(c.Database.Port); err != nil || !validPort(port) {
+				errs = append(errs, fmt.Errorf("database.port must be between 1 and 65535, got %q", c.Database.Port))
+			}
+		}
+	}
+
+	if c.Redis.URL == "" && !validPort(c.Redis.Port) {
+		errs = append(errs, fmt.Errorf("redis.port must be between 1 and 65535, got %d", c.Redis.Port))
+	}
+
+	if c.Server.Mode == "release" && c.JWT.SigningSecret() == "" {
+		errs = append(errs, errors.New("jwt.secret or jwt.secrets is required in release mode"))
+	}
+
+	for _, cidr := range c.RateLimit.ExemptCIDRs {
+		if !validCIDR(cidr) {
+			errs = append(errs, fmt.Errorf("rate_limit.exempt_cidrs has invalid CIDR or IP %q", cidr))
+		}
+	}
+
+	if c.JWT.ExpireTime <= 0 {
+		errs = append(errs, fmt.Errorf("jwt.expire_time must be positive, got %s", c.JWT.ExpireTime))
+	} else if c.JWT.MaxExpireTime > 0 && c.JWT.ExpireTime > c.JWT.MaxExpireTime {
+		log.Printf("config: jwt.expire_time %s exceeds jwt.max_expire_time %s", c.JWT.ExpireTime, c.JWT.MaxExpireTime)
+	}
+
+	if err := c.S3.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// ValidateForProduction rejects this outright
+	if c.Server.Mode == "release" && c.CORS.allowsAnyOrigin() {
+		log.Printf("config: cors.allowed_origins is \"*\" in release mode, a wildcard origin with credentials is unsafe in production")
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateForProduction runs Validate and additionally rejects settings that
+// are only acceptable in development: a wildcard CORS origin and sslmode=disable
+func (c *Config) ValidateForProduction() error {
+	errs := []error{c.Validate()}
+
+	if c.CORS.allowsAnyOrigin() {
+		errs = append(errs, errors.New("cors.allowed_origins must not be \"*\" in production"))
+	}
+
+	if c.Database.sslDisabled() {
+		errs = append(errs, errors.New("database.sslmode must not be \"disable\" in production"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// allowsAnyOrigin reports whether the allowed origins include the "*" wildcard
+func (c *CORSConfig) allowsAnyOrigin() bool {
+	for _, origin := range splitList(c.AllowedOrigins) {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// CORSOriginsForMode derives the allowed origins for a server mode from a
+// comma-separated list such as CORS_ALLOWED_ORIGINS. Outside release mode an
+// empty list allows any origin. In release mode the "*" wildcard is dropped,
+// and an empty result means no origin is safe to allow and CORS should stay disabled.
+func CORSOriginsForMode(mode, origins string) string {
+	items := splitList(origins)
+	if mode != "release" {
+		if len(items) == 0 {
+			return "*"
+		}
+		return strings.Join(items, ",")
+	}
+
+	safe := make([]string, 0, len(items))
+	for _, origin := range items {
+		if origin != "*" {
+			safe = append(safe, origin)
+		}
+	}
+	return strings.Join(safe, ",")
+}
+
+// sslDisabled reports whether the connection GetDSN produces has sslmode=disable
+func (c *DatabaseConfig) sslDisabled() bool {
+	if c.URL != "" {
+		u, err := url.Parse(c.URL)
+		return err == nil && u.Query().Get("sslmode") == "disable"
+	}
+	return c.SSLMode == "" || c.SSLMode == "disable"
+}
+
+// validCIDR reports whether value is a CIDR or a single IP address
+func validCIDR(value string) bool {
+	if _, _, err := net.ParseCIDR(value); err == nil {
+		return true
+	}
+	return net.ParseIP(value) != nil
+}
+
+// validPort reports whether port is a usable TCP port number
+func validPort(port int) bool {
+	return port >= 1 && port <= 65535
+}
+
+// validate checks that a custom endpoint has a region to sign requests with.
+// MinIO ignores the region but its client still needs one, so it defaults to us-east-1.
+func (c *S3Config) validate() error {
+	if c.Endpoint == "" || c.Region != "" {
+		return nil
+	}
+
+	host := endpointHost(c.Endpoint)
+	if isAWSHost(host) {
+		return nil
+	}
+	if isMinIOHost(host) {
+		c.Region = defaultMinIORegion
+		log.Printf("config: s3.region not set for MinIO endpoint %q, defaulting to %s", c.Endpoint, defaultMinIORegion)
+		return nil
+	}
+	return errors.New("s3.region is required when s3.endpoint is not an AWS host")
+}
+
+// endpointHost returns the lowercase host of an endpoint with or without scheme
+func endpointHost(endpoint string) string {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return strings.ToLower(endpoint)
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// isAWSHost reports whether host is an AWS S3 endpoint
+func isAWSHost(host string) bool {
+	return strings.HasSuffix(host, ".amazonaws.com") || strings.HasSuffix(host, ".amazonaws.com.cn")
+}
+
+// isMinIOHost reports whether host looks like a MinIO deployment
+func isMinIOHost(host string) bool {
+	return strings.Contains(host, "minio") || host == "localhost" || host == "127.0.0.1"
+}
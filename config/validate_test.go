@@ -0,0 +1,281 @@
+package config
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validConfig returns a default config that passes Validate
+func validConfig() *Config {
+	cfg := DefaultConfig()
+	cfg.Database.DBName = "app"
+	return cfg
+}
+
+func TestValidateDefaultsIsValid(t *testing.T) {
+	if err := validConfig().Validate(); err != nil {
+		t.Fatalf("Validate() = %v, want nil", err)
+	}
+}
+
+func TestValidateS3MinIOEndpoint(t *testing.T) {
+	tests := []struct {
+		name       string
+		endpoint   string
+		region     string
+		wantRegion string
+	}{
+		{"minio without region defaults", "http://minio:9000", "", defaultMinIORegion},
+		{"localhost without region defaults", "localhost:9000", "", defaultMinIORegion},
+		{"minio with region keeps it", "http://minio:9000", "ap-northeast-2", "ap-northeast-2"},
+		{"aws without region", "https://s3.ap-northeast-2.amazonaws.com", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.S3.Endpoint = tt.endpoint
+			cfg.S3.Region = tt.region
+
+			if err := cfg.Validate(); err != nil {
+				t.Fatalf("Validate() = %v, want nil", err)
+			}
+			if cfg.S3.Region != tt.wantRegion {
+				t.Errorf("region = %q, want %q", cfg.S3.Region, tt.wantRegion)
+			}
+		})
+	}
+}
+
+func TestValidateS3CustomEndpointRequiresRegion(t *testing.T) {
+	cfg := validConfig()
+	cfg.S3.Endpoint = "https://storage.example.com"
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "s3.region is required") {
+		t.Fatalf("Validate() = %v, want s3.region error", err)
+	}
+
+	cfg.S3.Region = "kr-central-1"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() with region = %v, want nil", err)
+	}
+}
+
+func TestValidateAggregatesErrors(t *testing.T) {
+	cfg := validConfig()
+	cfg.Server.Port = 0
+	cfg.Database.Host = ""
+	cfg.Database.Port = "abc"
+	cfg.Redis.Port = 70000
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want errors")
+	}
+	for _, want := range []string{"server.port", "database requires", "database.port", "redis.port"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %q, missing %q", err, want)
+		}
+	}
+}
+
+func TestValidateDatabaseURLSatisfiesDatabase(t *testing.T) {
+	cfg := validConfig()
+	cfg.Database.Host, cfg.Database.DBName = "", ""
+	cfg.Database.URL = "postgres://user:pass@db:5432/app"
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() = %v, want nil", err)
+	}
+}
+
+func TestValidateReleaseRequiresJWTSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		secrets []string
+		wantErr bool
+	}{
+		{"no secret", "", nil, true},
+		{"secret", "current", nil, false},
+		{"secrets only", "", []string{"current", "previous"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Server.Mode = "release"
+			cfg.JWT.Secret, cfg.JWT.Secrets = tt.secret, tt.secrets
+
+			err := cfg.Validate()
+			if gotErr := err != nil && strings.Contains(err.Error(), "jwt.secret"); gotErr != tt.wantErr {
+				t.Errorf("Validate() = %v, want jwt error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateForProduction(t *testing.T) {
+	cfg := validConfig()
+	cfg.Server.Mode = "release"
+	cfg.JWT.Secret = "secret"
+	cfg.CORS.AllowedOrigins = "*"
+	cfg.Database.SSLMode = "disable"
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() = %v, want nil outside production checks", err)
+	}
+	err := cfg.ValidateForProduction()
+	if err == nil || !strings.Contains(err.Error(), "cors.allowed_origins") || !strings.Contains(err.Error(), "database.sslmode") {
+		t.Fatalf("ValidateForProduction() = %v, want cors and sslmode errors", err)
+	}
+
+	cfg.CORS.AllowedOrigins = "https://app.example.com"
+	cfg.Database.SSLMode = "require"
+	if err := cfg.ValidateForProduction(); err != nil {
+		t.Fatalf("ValidateForProduction() = %v, want nil", err)
+	}
+}
+
+// captureLog redirects the standard logger into the returned buffer for the test
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	out, flags := log.Writer(), log.Flags()
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	t.Cleanup(func() {
+		log.SetOutput(out)
+		log.SetFlags(flags)
+	})
+	return &buf
+}
+
+func TestValidateWarnsOnWildcardCORSInRelease(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		origins string
+		warn    bool
+	}{
+		{"release wildcard", "release", "*", true},
+		{"release wildcard in list", "release", "https://app.example.com, *", true},
+		{"release allowlist", "release", "https://app.example.com", false},
+		{"debug wildcard", "debug", "*", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureLog(t)
+			cfg := validConfig()
+			cfg.Server.Mode = tt.mode
+			cfg.JWT.Secret = "secret"
+			cfg.CORS.AllowedOrigins = tt.origins
+
+			if err := cfg.Validate(); err != nil {
+				t.Fatalf("Validate() = %v, want only a warning", err)
+			}
+			if warned := strings.Contains(logs.String(), "cors.allowed_origins"); warned != tt.warn {
+				t.Errorf("warned = %v, want %v (log %q)", warned, tt.warn, logs.String())
+			}
+		})
+	}
+}
+
+func TestCORSOriginsForMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		origins string
+		want    string
+	}{
+		{"dev empty is permissive", "debug", "", "*"},
+		{"dev list kept", "debug", "http://localhost:3000, *", "http://localhost:3000,*"},
+		{"release drops wildcard", "release", "https://app.example.com, *", "https://app.example.com"},
+		{"release wildcard only", "release", "*", ""},
+		{"release empty", "release", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CORSOriginsForMode(tt.mode, tt.origins); got != tt.want {
+				t.Errorf("CORSOriginsForMode(%q, %q) = %q, want %q", tt.mode, tt.origins, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateJWTExpiry(t *testing.T) {
+	tests := []struct {
+		name    string
+		expire  time.Duration
+		wantErr bool
+		warn    bool
+	}{
+		{"normal", time.Hour, false, false},
+		{"at max", 30 * 24 * time.Hour, false, false},
+		{"over max", 90 * 24 * time.Hour, false, true},
+		{"zero", 0, true, false},
+		{"negative", -time.Minute, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureLog(t)
+			cfg := validConfig()
+			cfg.JWT.ExpireTime = tt.expire
+
+			err := cfg.Validate()
+			if gotErr := err != nil && strings.Contains(err.Error(), "jwt.expire_time"); gotErr != tt.wantErr {
+				t.Errorf("Validate() = %v, want expiry error %v", err, tt.wantErr)
+			}
+			if warned := strings.Contains(logs.String(), "jwt.max_expire_time"); warned != tt.warn {
+				t.Errorf("warned = %v, want %v (log %q)", warned, tt.warn, logs.String())
+			}
+		})
+	}
+}
+
+func TestValidateJWTExpiryWithoutMax(t *testing.T) {
+	logs := captureLog(t)
+	cfg := validConfig()
+	cfg.JWT.ExpireTime = 365 * 24 * time.Hour
+	cfg.JWT.MaxExpireTime = 0
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() = %v, want nil", err)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("warned %q with no max configured", logs.String())
+	}
+}
+
+func TestJWTEffectiveExpiry(t *testing.T) {
+	tests := []struct {
+		expire time.Duration
+		want   time.Duration
+	}{
+		{2 * time.Hour, 2 * time.Hour},
+		{0, 24 * time.Hour},
+		{-time.Hour, 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		cfg := JWTConfig{ExpireTime: tt.expire}
+		if got := cfg.EffectiveExpiry(); got != tt.want {
+			t.Errorf("EffectiveExpiry() with ExpireTime %s = %s, want %s", tt.expire, got, tt.want)
+		}
+	}
+}
+
+func TestValidateRateLimitExemptCIDRs(t *testing.T) {
+	cfg := validConfig()
+	cfg.RateLimit.ExemptCIDRs = []string{"10.0.0.0/8", "192.0.2.1", "2001:db8::/32"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() = %v, want nil", err)
+	}
+
+	cfg.RateLimit.ExemptCIDRs = []string{"10.0.0.0/33", "internal"}
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), `"10.0.0.0/33"`) || !strings.Contains(err.Error(), `"internal"`) {
+		t.Errorf("Validate() = %v, want an error for each invalid entry", err)
+	}
+}
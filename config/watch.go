@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce collapses the burst of events editors emit for a single save
+const watchDebounce = 500 * time.Millisecond
+
+// Watch re-runs Load for path whenever the file changes and passes the fresh
+// config to onReload; nothing is applied automatically. The parent directory is
+// watched rather than the file so atomic saves (write temp file, rename over)
+// keep being picked up. Reload errors are logged and the previous configuration
+// stays in effect. The returned function stops watching.
+func Watch(path string, onReload func(*Config)) (stop func(), err error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config watcher: %w", err)
+	}
+
+	target := filepath.Clean(path)
+	if err := watcher.Add(filepath.Dir(target)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to watch config %q: %w", path, err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		var timer *time.Timer
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != target || event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+					continue
+				}
+				if timer == nil {
+					timer = time.AfterFunc(watchDebounce, func() {
+						if err := reload(path, onReload); err != nil {
+							log.Printf("config: reload of %q failed: %v", path, err)
+						}
+					})
+				} else {
+					timer.Reset(watchDebounce)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("config: watching %q: %v", path, err)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			watcher.Close()
+		})
+	}, nil
+}
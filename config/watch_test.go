@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// watchConfig starts Watch on path, sending each reloaded config to the returned channel
+func watchConfig(t *testing.T, path string) <-chan *Config {
+	t.Helper()
+	reloads := make(chan *Config, 4)
+	stop, err := Watch(path, func(cfg *Config) { reloads <- cfg })
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	t.Cleanup(stop)
+	return reloads
+}
+
+// nextReload waits for a reload, failing after a few debounce windows
+func nextReload(t *testing.T, reloads <-chan *Config) *Config {
+	t.Helper()
+	select {
+	case cfg := <-reloads:
+		return cfg
+	case <-time.After(5 * watchDebounce):
+		t.Fatal("no reload")
+		return nil
+	}
+}
+
+// noReload fails if a reload arrives within a few debounce windows
+func noReload(t *testing.T, reloads <-chan *Config) {
+	t.Helper()
+	select {
+	case cfg := <-reloads:
+		t.Fatalf("unexpected reload with %+v", cfg.Server)
+	case <-time.After(3 * watchDebounce):
+	}
+}
+
+func TestWatchReloadsOnWrite(t *testing.T) {
+	t.Setenv("PORT", "")
+	t.Setenv("SERVER_PORT", "")
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "server:\n  port: 9090\n")
+	reloads := watchConfig(t, path)
+
+	// Several writes in a row are debounced into one reload
+	writeConfig(t, dir, "server:\n  port: 9191\n")
+	writeConfig(t, dir, "server:\n  port: 9292\n")
+	if cfg := nextReload(t, reloads); cfg.Server.Port != 9292 {
+		t.Errorf("port = %d, want 9292", cfg.Server.Port)
+	}
+	noReload(t, reloads)
+}
+
+func TestWatchSurvivesAtomicSave(t *testing.T) {
+	t.Setenv("PORT", "")
+	t.Setenv("SERVER_PORT", "")
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "server:\n  port: 9090\n")
+	reloads := watchConfig(t, path)
+
+	for _, port := range []int{9191, 9292} {
+		temp := filepath.Join(dir, "config.yaml.tmp")
+		if err := os.WriteFile(temp, []byte(fmt.Sprintf("server:\n  port: %d\n", port)), 0o644); err != nil {
+			t.Fatalf("write temp: %v", err)
+		}
+		if err := os.Rename(temp, path); err != nil {
+			t.Fatalf("rename: %v", err)
+		}
+		if cfg := nextReload(t, reloads); cfg.Server.Port != port {
+			t.Errorf("port after atomic save = %d, want %d", cfg.Server.Port, port)
+		}
+	}
+}
+
+func TestWatchSkipsUnreadableChanges(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "server:\n  port: 9090\n")
+	reloads := watchConfig(t, path)
+
+	writeConfig(t, dir, "server: [unclosed\n")
+	noReload(t, reloads)
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	noReload(t, reloads)
+}
+
+func TestWatchIgnoresOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "server:\n  port: 9090\n")
+	reloads := watchConfig(t, path)
+
+	if err := os.WriteFile(filepath.Join(dir, "other.yaml"), []byte("x: 1\n"), 0o644); err != nil {
+		t.Fatalf("write other: %v", err)
+	}
+	noReload(t, reloads)
+}
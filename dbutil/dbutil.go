@@ -0,0 +1,59 @@
+// Package dbutil provides gorm helpers shared by all services.
+package dbutil
+
+import (
+	"context"
+	"time"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/config"
+	"gorm.io/gorm"
+)
+
+// Read returns db bound to a context with cfg.ReadTimeout applied. Call cancel
+// once the query completes.
+func Read(ctx context.Context, db *gorm.DB, cfg config.DatabaseConfig) (*gorm.DB, context.CancelFunc) {
+	return withTimeout(ctx, db, cfg.ReadTimeout)
+}
+
+// Write returns db bound to a context with cfg.WriteTimeout applied. Call
+// cancel once the statement or transaction completes.
+func Write(ctx context.Context, db *gorm.DB, cfg config.DatabaseConfig) (*gorm.DB, context.CancelFunc) {
+	return withTimeout(ctx, db, cfg.WriteTimeout)
+}
+
+// withTimeout binds db to ctx with a deadline; a timeout of 0 adds none
+func withTimeout(ctx context.Context, db *gorm.DB, timeout time.Duration) (*gorm.DB, context.CancelFunc) {
+	if timeout <= 0 {
+		ctx, cancel := context.WithCancel(ctx)
+		return db.WithContext(ctx), cancel
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return db.WithContext(ctx), cancel
+}
+
+// Paginate counts the rows matched by db and loads page (1-based) of perPage
+// rows into dest, a pointer to a slice. Conditions, scopes and ordering already
+// applied to db are respected. The total is meant for response.Paginated.
+func Paginate(db *gorm.DB, page, perPage int, dest interface{}) (total int64, err error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 1
+	}
+
+	query := db
+	if query.Statement.Model == nil && query.Statement.Table == "" {
+		query = query.Model(dest)
+	}
+	// New session so the count and the fetch don't share statement state
+	query = query.Session(&gorm.Session{})
+
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	if err := query.Offset((page - 1) * perPage).Limit(perPage).Find(dest).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
@@ -0,0 +1,167 @@
+package dbutil
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/config"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB opens an in-memory SQLite database
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+// remaining returns how long until the deadline of db's context
+func remaining(t *testing.T, db *gorm.DB) time.Duration {
+	t.Helper()
+	deadline, ok := db.Statement.Context.Deadline()
+	if !ok {
+		t.Fatal("no deadline on the bound context")
+	}
+	return time.Until(deadline)
+}
+
+func TestReadAndWriteApplyTheirTimeouts(t *testing.T) {
+	db := openTestDB(t)
+	cfg := config.DatabaseConfig{ReadTimeout: 5 * time.Second, WriteTimeout: time.Second}
+
+	read, cancelRead := Read(context.Background(), db, cfg)
+	defer cancelRead()
+	if d := remaining(t, read); d <= 4*time.Second || d > 5*time.Second {
+		t.Errorf("read deadline in %s, want about 5s", d)
+	}
+
+	write, cancelWrite := Write(context.Background(), db, cfg)
+	defer cancelWrite()
+	if d := remaining(t, write); d <= 0 || d > time.Second {
+		t.Errorf("write deadline in %s, want about 1s", d)
+	}
+}
+
+func TestZeroTimeoutAddsNoDeadline(t *testing.T) {
+	read, cancel := Read(context.Background(), openTestDB(t), config.DatabaseConfig{})
+	if _, ok := read.Statement.Context.Deadline(); ok {
+		t.Error("deadline set with a zero timeout")
+	}
+	cancel()
+	if read.Statement.Context.Err() == nil {
+		t.Error("cancel did not cancel the bound context")
+	}
+}
+
+func TestWriteTimeoutCancelsStatement(t *testing.T) {
+	db := openTestDB(t)
+	write, cancel := Write(context.Background(), db, config.DatabaseConfig{WriteTimeout: time.Nanosecond})
+	defer cancel()
+	time.Sleep(time.Millisecond)
+
+	if err := write.Exec("CREATE TABLE t (id INTEGER)").Error; err == nil {
+		t.Error("statement ran after the write deadline passed")
+	}
+}
+
+type paginateItem struct {
+	ID        uint
+	Workspace string
+	Name      string
+}
+
+// seedItems creates a table of n items, alternating between workspaces a and b
+func seedItems(t *testing.T, db *gorm.DB, n int) {
+	t.Helper()
+	if err := db.AutoMigrate(&paginateItem{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	items := make([]paginateItem, n)
+	for i := range items {
+		items[i] = paginateItem{Workspace: []string{"a", "b"}[i%2], Name: fmt.Sprintf("item-%02d", i+1)}
+	}
+	if err := db.Create(&items).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+}
+
+// names returns the names of items
+func names(items []paginateItem) []string {
+	result := make([]string, len(items))
+	for i, item := range items {
+		result[i] = item.Name
+	}
+	return result
+}
+
+func TestPaginate(t *testing.T) {
+	db := openTestDB(t)
+	seedItems(t, db, 25)
+
+	tests := []struct {
+		name    string
+		page    int
+		perPage int
+		want    []string
+	}{
+		{"first page", 1, 10, []string{"item-01", "item-02", "item-03", "item-04", "item-05", "item-06", "item-07", "item-08", "item-09", "item-10"}},
+		{"last partial page", 3, 10, []string{"item-21", "item-22", "item-23", "item-24", "item-25"}},
+		{"past the end", 4, 10, []string{}},
+		{"page below 1", 0, 2, []string{"item-01", "item-02"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var items []paginateItem
+			total, err := Paginate(db.Order("id"), tt.page, tt.perPage, &items)
+			if err != nil {
+				t.Fatalf("Paginate: %v", err)
+			}
+			if total != 25 {
+				t.Errorf("total = %d, want 25", total)
+			}
+			if got := names(items); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("page = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginateRespectsConditions(t *testing.T) {
+	db := openTestDB(t)
+	seedItems(t, db, 25)
+
+	inWorkspace := func(workspace string) func(*gorm.DB) *gorm.DB {
+		return func(db *gorm.DB) *gorm.DB { return db.Where("workspace = ?", workspace) }
+	}
+
+	var items []paginateItem
+	total, err := Paginate(db.Scopes(inWorkspace("b")).Where("name > ?", "item-10").Order("id DESC"), 1, 3, &items)
+	if err != nil {
+		t.Fatalf("Paginate: %v", err)
+	}
+	// Workspace b holds the even items; those after item-10 are 12 through 24
+	if total != 7 {
+		t.Errorf("total = %d, want 7", total)
+	}
+	if got, want := names(items), []string{"item-24", "item-22", "item-20"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("page = %v, want %v", got, want)
+	}
+}
+
+func TestPaginateReportsErrors(t *testing.T) {
+	var items []paginateItem
+	if _, err := Paginate(openTestDB(t), 1, 10, &items); err == nil {
+		t.Error("Paginate on a missing table = nil, want an error")
+	}
+}
@@ -0,0 +1,128 @@
+// Package diagnostics provides authenticated debug endpoints for incident response.
+package diagnostics
+
+import (
+	"time"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/config"
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/health"
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// startTime approximates process start for uptime reporting
+var startTime = time.Now()
+
+// BuildInfo describes the running binary
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime,omitempty"`
+}
+
+// Options holds the sources the debug endpoints report on
+type Options struct {
+	Build  BuildInfo
+	Config *config.Config  // optional
+	Health *health.Handler // optional
+
+	// Authorize decides whether the caller may access diagnostics.
+	// All requests are rejected when it is nil.
+	Authorize func(c *gin.Context) bool
+
+	// ConfigInRelease allows /debug/config while gin runs in release mode
+	ConfigInRelease bool
+}
+
+// InfoResponse is the /debug/info payload
+type InfoResponse struct {
+	Build      BuildInfo              `json:"build"`
+	ConfigHash string                 `json:"configHash,omitempty"`
+	StartedAt  string                 `json:"startedAt"`
+	Uptime     string                 `json:"uptime"`
+	Readiness  *health.HealthResponse `json:"readiness,omitempty"`
+}
+
+// InfoHandler returns the /debug/info handler combining build info, the
+// redacted config hash, uptime and the last readiness result
+func InfoHandler(opts Options) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !authorized(c, opts) {
+			return
+		}
+
+		info := InfoResponse{
+			Build:     opts.Build,
+			StartedAt: startTime.UTC().Format(time.RFC3339),
+			Uptime:    time.Since(startTime).Round(time.Second).String(),
+		}
+		if opts.Config != nil {
+			info.ConfigHash = opts.Config.Hash()
+		}
+		if opts.Health != nil {
+			if readiness, ok := opts.Health.LastReadiness(); ok {
+				info.Readiness = &readiness
+			}
+		}
+
+		response.OK(c, info)
+	}
+}
+
+// ConfigHandler returns the /debug/config handler serving the redacted
+// effective config under the same snake_case keys as the YAML file.
+// It responds 404 in release mode unless opts.ConfigInRelease is set.
+func ConfigHandler(opts Options) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if opts.Config == nil || (gin.Mode() == gin.ReleaseMode && !opts.ConfigInRelease) {
+			response.NotFound(c, "Not found")
+			return
+		}
+		if !authorized(c, opts) {
+			return
+		}
+
+		effective, err := yamlFields(opts.Config.Redacted())
+		if err != nil {
+			response.InternalError(c, "Failed to encode config")
+			return
+		}
+		response.OK(c, effective)
+	}
+}
+
+// yamlFields round-trips cfg through YAML so it is keyed by the config file's
+// field names rather than the Go field names
+func yamlFields(cfg *config.Config) (map[string]interface{}, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// RegisterRoutes registers the diagnostics routes
+func RegisterRoutes(router gin.IRouter, opts Options) {
+	router.GET("/debug/info", InfoHandler(opts))
+}
+
+// RegisterConfigRoute registers /debug/config. It is kept separate from
+// RegisterRoutes so exposing configuration is always an explicit choice.
+func RegisterConfigRoute(router gin.IRouter, opts Options) {
+	router.GET("/debug/config", ConfigHandler(opts))
+}
+
+// authorized checks access and writes a 401 when it is denied
+func authorized(c *gin.Context, opts Options) bool {
+	if opts.Authorize == nil || !opts.Authorize(c) {
+		response.Unauthorized(c, "Authentication required")
+		c.Abort()
+		return false
+	}
+	return true
+}
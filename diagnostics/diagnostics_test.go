@@ -0,0 +1,143 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/config"
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/health"
+	"github.com/gin-gonic/gin"
+)
+
+// tokenAuth authorizes requests carrying X-Debug-Token: letmein
+func tokenAuth(c *gin.Context) bool {
+	return c.GetHeader("X-Debug-Token") == "letmein"
+}
+
+// serveDebug sends GET path to router, with the debug token when authorized is true
+func serveDebug(router *gin.Engine, path string, authorized bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if authorized {
+		req.Header.Set("X-Debug-Token", "letmein")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestInfoHandlerCombinesSections(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	cfg := config.DefaultConfig()
+	h := health.NewHandler()
+
+	router := gin.New()
+	h.RegisterRoutes(router)
+	RegisterRoutes(router, Options{
+		Build:     BuildInfo{Version: "1.4.0", Commit: "abc123"},
+		Config:    cfg,
+		Health:    h,
+		Authorize: tokenAuth,
+	})
+	serveDebug(router, "/ready", false)
+
+	w := serveDebug(router, "/debug/info", true)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var body struct {
+		Data InfoResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	info := body.Data
+	if info.Build.Version != "1.4.0" || info.Build.Commit != "abc123" {
+		t.Errorf("build = %+v", info.Build)
+	}
+	if info.ConfigHash != cfg.Hash() {
+		t.Errorf("configHash = %q, want %q", info.ConfigHash, cfg.Hash())
+	}
+	if info.StartedAt == "" || info.Uptime == "" {
+		t.Errorf("startedAt %q, uptime %q, want both set", info.StartedAt, info.Uptime)
+	}
+	if info.Readiness == nil || info.Readiness.Status != health.StatusHealthy {
+		t.Errorf("readiness = %+v, want the last healthy result", info.Readiness)
+	}
+}
+
+func TestInfoHandlerRequiresAuth(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	for name, opts := range map[string]Options{
+		"denied":       {Authorize: tokenAuth},
+		"no authorize": {},
+	} {
+		router := gin.New()
+		RegisterRoutes(router, opts)
+		if w := serveDebug(router, "/debug/info", name == "no authorize"); w.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status = %d, want 401", name, w.Code)
+		}
+	}
+}
+
+// configRouter returns a router serving /debug/config for cfg
+func configRouter(cfg *config.Config, inRelease bool) *gin.Engine {
+	router := gin.New()
+	RegisterConfigRoute(router, Options{Config: cfg, Authorize: tokenAuth, ConfigInRelease: inRelease})
+	return router
+}
+
+func TestConfigHandlerMasksSecretsUnderYAMLKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	defer gin.SetMode(gin.ReleaseMode)
+	cfg := config.DefaultConfig()
+	cfg.Database.Password = "db-password"
+	cfg.Database.DBName = "app"
+	cfg.JWT.Secret = "jwt-secret"
+	cfg.JWT.Secrets = []string{"previous-secret"}
+	cfg.Redis.Password = "redis-password"
+	cfg.S3.SecretKey = "s3-secret"
+
+	w := serveDebug(configRouter(cfg, false), "/debug/config", true)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	for _, secret := range []string{"db-password", "jwt-secret", "previous-secret", "redis-password", "s3-secret"} {
+		if strings.Contains(w.Body.String(), secret) {
+			t.Errorf("response exposes %q", secret)
+		}
+	}
+
+	var body struct {
+		Data map[string]map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body.Data["database"]["dbname"] != "app" {
+		t.Errorf("database = %v, want the dbname YAML key", body.Data["database"])
+	}
+	if _, ok := body.Data["server"]["shutdown_timeout"]; !ok {
+		t.Errorf("server = %v, want snake_case keys", body.Data["server"])
+	}
+	if _, ok := body.Data["Server"]; ok {
+		t.Error("response uses Go field names")
+	}
+}
+
+func TestConfigHandlerDisabledInRelease(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	cfg := config.DefaultConfig()
+
+	if w := serveDebug(configRouter(cfg, false), "/debug/config", true); w.Code != http.StatusNotFound {
+		t.Errorf("release mode: status = %d, want 404", w.Code)
+	}
+	if w := serveDebug(configRouter(cfg, true), "/debug/config", true); w.Code != http.StatusOK {
+		t.Errorf("release mode with ConfigInRelease: status = %d, want 200", w.Code)
+	}
+	if w := serveDebug(configRouter(cfg, true), "/debug/config", false); w.Code != http.StatusUnauthorized {
+		t.Errorf("unauthorized: status = %d, want 401", w.Code)
+	}
+}
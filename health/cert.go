@@ -0,0 +1,74 @@
+package health
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"time"
+)
+
+// CertExpiryChecker reports on how close a certificate is to expiring
+type CertExpiryChecker struct {
+	getCert    func() *x509.Certificate
+	warnBefore time.Duration
+}
+
+// NewCertExpiryChecker creates a checker that reports degraded within warnBefore
+// of the certificate's expiry and unhealthy once it has expired
+func NewCertExpiryChecker(getCert func() *x509.Certificate, warnBefore time.Duration) *CertExpiryChecker {
+	return &CertExpiryChecker{getCert: getCert, warnBefore: warnBefore}
+}
+
+// Name returns the checker name
+func (c *CertExpiryChecker) Name() string {
+	return "certificate"
+}
+
+// Check performs the certificate expiry check
+func (c *CertExpiryChecker) Check(ctx context.Context) ComponentCheck {
+	cert := c.getCert()
+	if cert == nil {
+		return ComponentCheck{
+			Status:  StatusUnhealthy,
+			Message: "No certificate available",
+		}
+	}
+
+	remaining := time.Until(cert.NotAfter)
+
+	switch {
+	case remaining <= 0:
+		return ComponentCheck{
+			Status:  StatusUnhealthy,
+			Message: fmt.Sprintf("Certificate expired %s ago", formatRemaining(-remaining)),
+		}
+	case remaining <= c.warnBefore:
+		return ComponentCheck{
+			Status:  StatusDegraded,
+			Message: fmt.Sprintf("Certificate expires in %s", formatRemaining(remaining)),
+		}
+	default:
+		return ComponentCheck{
+			Status:  StatusHealthy,
+			Message: fmt.Sprintf("Certificate valid for %s", formatRemaining(remaining)),
+		}
+	}
+}
+
+// formatRemaining formats d in whole days, or in hours rounded up when under a
+// day so a certificate about to expire is not reported as "0 days"
+func formatRemaining(d time.Duration) string {
+	if d < 24*time.Hour {
+		hours := int(math.Ceil(d.Hours()))
+		if hours <= 1 {
+			return "1 hour"
+		}
+		return fmt.Sprintf("%d hours", hours)
+	}
+	days := int(d.Hours() / 24)
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
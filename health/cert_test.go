@@ -0,0 +1,49 @@
+package health
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+)
+
+// certExpiringIn returns a cert source whose certificate expires d from now
+func certExpiringIn(d time.Duration) func() *x509.Certificate {
+	cert := &x509.Certificate{NotAfter: time.Now().Add(d)}
+	return func() *x509.Certificate { return cert }
+}
+
+func TestCertExpiryChecker(t *testing.T) {
+	warnBefore := 7 * 24 * time.Hour
+	tests := []struct {
+		name        string
+		expiresIn   time.Duration
+		wantStatus  Status
+		wantMessage string
+	}{
+		{"valid", 30*24*time.Hour + time.Minute, StatusHealthy, "Certificate valid for 30 days"},
+		{"expires in a day", 24*time.Hour + time.Minute, StatusDegraded, "Certificate expires in 1 day"},
+		{"expires within hours", 5*time.Hour + 30*time.Minute, StatusDegraded, "Certificate expires in 6 hours"},
+		{"expires within the hour", 10 * time.Minute, StatusDegraded, "Certificate expires in 1 hour"},
+		{"expired yesterday", -24*time.Hour - time.Minute, StatusUnhealthy, "Certificate expired 1 day ago"},
+		{"expired hours ago", -2*time.Hour - 30*time.Minute, StatusUnhealthy, "Certificate expired 3 hours ago"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := NewCertExpiryChecker(certExpiringIn(tt.expiresIn), warnBefore).Check(context.Background())
+			if check.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", check.Status, tt.wantStatus)
+			}
+			if check.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", check.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestCertExpiryCheckerNoCertificate(t *testing.T) {
+	checker := NewCertExpiryChecker(func() *x509.Certificate { return nil }, time.Hour)
+	if check := checker.Check(context.Background()); check.Status != StatusUnhealthy {
+		t.Errorf("status = %s, want unhealthy", check.Status)
+	}
+}
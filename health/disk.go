@@ -0,0 +1,51 @@
+package health
+
+import (
+	"context"
+	"fmt"
+)
+
+// DiskChecker checks free space on the volume holding a path
+type DiskChecker struct {
+	name         string
+	path         string
+	minFreeBytes uint64
+}
+
+// NewDiskChecker creates a checker that reports degraded when free space on
+// path's volume drops below minFreeBytes and unhealthy below half of it
+func NewDiskChecker(name, path string, minFreeBytes uint64) *DiskChecker {
+	return &DiskChecker{name: name, path: path, minFreeBytes: minFreeBytes}
+}
+
+// Name returns the checker name
+func (d *DiskChecker) Name() string {
+	return d.name
+}
+
+// Check performs the disk space check
+func (d *DiskChecker) Check(ctx context.Context) ComponentCheck {
+	free, total, supported, err := diskUsage(d.path)
+	if !supported {
+		return ComponentCheck{
+			Status:  StatusHealthy,
+			Message: "Disk space check not supported on this platform",
+		}
+	}
+	if err != nil {
+		return ComponentCheck{
+			Status:  StatusUnhealthy,
+			Message: "Failed to read disk usage: " + err.Error(),
+		}
+	}
+
+	message := fmt.Sprintf("%d of %d bytes free", free, total)
+	switch {
+	case free < d.minFreeBytes/2:
+		return ComponentCheck{Status: StatusUnhealthy, Message: message}
+	case free < d.minFreeBytes:
+		return ComponentCheck{Status: StatusDegraded, Message: message}
+	default:
+		return ComponentCheck{Status: StatusHealthy, Message: message}
+	}
+}
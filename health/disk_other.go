@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !freebsd
+
+package health
+
+// diskUsage is not available on this platform
+func diskUsage(path string) (free, total uint64, supported bool, err error) {
+	return 0, 0, false, nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -22,9 +23,12 @@ const (
 
 // HealthResponse represents the health check response
 type HealthResponse struct {
-	Status    Status                   `json:"status"`
-	Timestamp string                   `json:"timestamp"`
+	Status    Status                    `json:"status"`
+	Timestamp string                    `json:"timestamp"`
 	Checks    map[string]ComponentCheck `json:"checks,omitempty"`
+	Version   string                    `json:"version,omitempty"`
+	Commit    string                    `json:"commit,omitempty"`
+	BuildTime string                    `json:"buildTime,omitempty"`
 }
 
 // ComponentCheck represents a single component's health
@@ -42,8 +46,26 @@ type Checker interface {
 
 // Handler holds health check dependencies
 type Handler struct {
-	checkers []Checker
-	mu       sync.RWMutex
+	checkers  []Checker
+	mu        sync.RWMutex
+	draining  atomic.Bool
+	started   atomic.Bool
+	failing   atomic.Bool // last readiness checks were unhealthy, ignoring draining
+	lastReady *HealthResponse
+
+	version   string
+	commit    string
+	buildTime string
+
+	cacheTTL   time.Duration
+	cache      map[string]cachedCheck
+	refreshing map[string]bool
+}
+
+// cachedCheck is a checker result and when it was taken
+type cachedCheck struct {
+	check     ComponentCheck
+	checkedAt time.Time
 }
 
 // NewHandler creates a new health handler
@@ -53,6 +75,18 @@ func NewHandler() *Handler {
 	}
 }
 
+// NewHandlerWithCache creates a health handler whose readiness probe reuses
+// each checker's result for ttl, so frequent probes do not hit dependencies
+// every time. Stale results are served while a background refresh runs; the
+// first probe runs checks synchronously.
+func NewHandlerWithCache(ttl time.Duration) *Handler {
+	h := NewHandler()
+	h.cacheTTL = ttl
+	h.cache = make(map[string]cachedCheck)
+	h.refreshing = make(map[string]bool)
+	return h
+}
+
 // AddChecker adds a health checker
 func (h *Handler) AddChecker(checker Checker) {
 	h.mu.Lock()
@@ -60,9 +94,38 @@ func (h *Handler) AddChecker(checker Checker) {
 	h.checkers = append(h.checkers, checker)
 }
 
-// HealthHandler returns the /health endpoint handler (liveness probe)
-func (h *Handler) HealthHandler() gin.HandlerFunc {
+// Drain makes the readiness probe report unhealthy so load balancers stop
+// routing traffic here ahead of shutdown
+func (h *Handler) Drain() {
+	h.draining.Store(true)
+}
+
+// SetBuildInfo sets the version, commit and build time reported by /health
+func (h *Handler) SetBuildInfo(version, commit, buildTime string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.version = version
+	h.commit = commit
+	h.buildTime = buildTime
+}
+
+// MarkStarted flips the startup probe to healthy once initialization such as
+// migrations and cache warm-up has finished
+func (h *Handler) MarkStarted() {
+	h.started.Store(true)
+}
+
+// StartedHandler returns the /started endpoint handler (startup probe).
+// It responds 503 until MarkStarted is called, then 200.
+func (h *Handler) StartedHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if !h.started.Load() {
+			c.JSON(http.StatusServiceUnavailable, HealthResponse{
+				Status:    StatusUnhealthy,
+				Timestamp: time.Now().UTC().Format(time.RFC3339),
+			})
+			return
+		}
 		c.JSON(http.StatusOK, HealthResponse{
 			Status:    StatusHealthy,
 			Timestamp: time.Now().UTC().Format(time.RFC3339),
@@ -70,9 +133,37 @@ func (h *Handler) HealthHandler() gin.HandlerFunc {
 	}
 }
 
+// HealthHandler returns the /health endpoint handler (liveness probe)
+func (h *Handler) HealthHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.mu.RLock()
+		resp := HealthResponse{
+			Status:    StatusHealthy,
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Version:   h.version,
+			Commit:    h.commit,
+			BuildTime: h.buildTime,
+		}
+		h.mu.RUnlock()
+
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
 // ReadyHandler returns the /ready endpoint handler (readiness probe)
 func (h *Handler) ReadyHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if h.draining.Load() {
+			h.respondReady(c, HealthResponse{
+				Status:    StatusUnhealthy,
+				Timestamp: time.Now().UTC().Format(time.RFC3339),
+				Checks: map[string]ComponentCheck{
+					"shutdown": {Status: StatusUnhealthy, Message: "Server is shutting down"},
+				},
+			})
+			return
+		}
+
 		h.mu.RLock()
 		checkers := h.checkers
 		h.mu.RUnlock()
@@ -83,23 +174,32 @@ func (h *Handler) ReadyHandler() gin.HandlerFunc {
 		checks := make(map[string]ComponentCheck)
 		overallStatus := StatusHealthy
 
+		// Run checkers in parallel so latency is the slowest check, not the sum
+		var (
+			wg        sync.WaitGroup
+			resultsMu sync.Mutex
+		)
 		for _, checker := range checkers {
-			check := checker.Check(ctx)
-			checks[checker.Name()] = check
-
-			if check.Status == StatusUnhealthy {
-				overallStatus = StatusUnhealthy
-			} else if check.Status == StatusDegraded && overallStatus == StatusHealthy {
-				overallStatus = StatusDegraded
-			}
-		}
-
-		statusCode := http.StatusOK
-		if overallStatus == StatusUnhealthy {
-			statusCode = http.StatusServiceUnavailable
+			wg.Add(1)
+			go func(checker Checker) {
+				defer wg.Done()
+				check := h.check(ctx, checker)
+
+				resultsMu.Lock()
+				defer resultsMu.Unlock()
+				checks[checker.Name()] = check
+
+				if check.Status == StatusUnhealthy {
+					overallStatus = StatusUnhealthy
+				} else if check.Status == StatusDegraded && overallStatus == StatusHealthy {
+					overallStatus = StatusDegraded
+				}
+			}(checker)
 		}
+		wg.Wait()
 
-		c.JSON(statusCode, HealthResponse{
+		h.failing.Store(overallStatus == StatusUnhealthy)
+		h.respondReady(c, HealthResponse{
 			Status:    overallStatus,
 			Timestamp: time.Now().UTC().Format(time.RFC3339),
 			Checks:    checks,
@@ -107,10 +207,101 @@ func (h *Handler) ReadyHandler() gin.HandlerFunc {
 	}
 }
 
+// check runs checker, or returns its cached result when caching is enabled
+func (h *Handler) check(ctx context.Context, checker Checker) ComponentCheck {
+	if h.cacheTTL <= 0 {
+		return checker.Check(ctx)
+	}
+
+	name := checker.Name()
+	h.mu.Lock()
+	cached, ok := h.cache[name]
+	if ok && time.Since(cached.checkedAt) >= h.cacheTTL && !h.refreshing[name] {
+		h.refreshing[name] = true
+		go h.refresh(checker)
+	}
+	h.mu.Unlock()
+
+	if ok {
+		return cached.check
+	}
+
+	// Cold start: nothing to serve yet
+	check := checker.Check(ctx)
+	h.store(name, check)
+	return check
+}
+
+// refresh re-runs checker in the background and caches the result
+func (h *Handler) refresh(checker Checker) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	check := checker.Check(ctx)
+	h.store(checker.Name(), check)
+}
+
+// store caches a checker result
+func (h *Handler) store(name string, check ComponentCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.cache[name] = cachedCheck{check: check, checkedAt: time.Now()}
+	delete(h.refreshing, name)
+}
+
+// respondReady records resp as the latest readiness result and writes it
+func (h *Handler) respondReady(c *gin.Context, resp HealthResponse) {
+	h.mu.Lock()
+	h.lastReady = &resp
+	h.mu.Unlock()
+
+	statusCode := http.StatusOK
+	if resp.Status == StatusUnhealthy {
+		statusCode = http.StatusServiceUnavailable
+	}
+	c.JSON(statusCode, resp)
+}
+
+// LastReadiness returns the result of the most recent readiness check
+func (h *Handler) LastReadiness() (HealthResponse, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.lastReady == nil {
+		return HealthResponse{}, false
+	}
+	return *h.lastReady, true
+}
+
+// Ready reports whether the service should accept traffic: it is not draining
+// and the most recent readiness check was not unhealthy. It does not run any
+// checks, and reports ready before the first readiness probe.
+func (h *Handler) Ready() bool {
+	if h.draining.Load() {
+		return false
+	}
+	readiness, ok := h.LastReadiness()
+	return !ok || readiness.Status != StatusUnhealthy
+}
+
+// Healthy reports whether the most recent readiness checks were not unhealthy,
+// regardless of draining, so requests still in flight during the pre-shutdown
+// delay can be served. It does not run any checks, and reports healthy before
+// the first readiness probe.
+func (h *Handler) Healthy() bool {
+	return !h.failing.Load()
+}
+
 // RegisterRoutes registers health check routes
 func (h *Handler) RegisterRoutes(router *gin.Engine) {
-	router.GET("/health", h.HealthHandler())
-	router.GET("/ready", h.ReadyHandler())
+	h.RegisterRoutesWithPaths(router, "/health", "/ready")
+}
+
+// RegisterRoutesWithPaths registers the liveness and readiness probes at custom
+// paths, e.g. /healthz and /readyz; the startup probe stays at /started
+func (h *Handler) RegisterRoutesWithPaths(router *gin.Engine, healthPath, readyPath string) {
+	router.GET(healthPath, h.HealthHandler())
+	router.GET(readyPath, h.ReadyHandler())
+	router.GET("/started", h.StartedHandler())
 }
 
 // DatabaseChecker checks database connectivity
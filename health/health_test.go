@@ -0,0 +1,113 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// staticChecker always reports status
+type staticChecker struct {
+	status Status
+}
+
+func (s staticChecker) Name() string { return "static" }
+
+func (s staticChecker) Check(ctx context.Context) ComponentCheck {
+	return ComponentCheck{Status: s.status}
+}
+
+// probeReady runs the readiness probe of h and returns the status code
+func probeReady(h *Handler) int {
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	h.RegisterRoutes(router)
+	return serveProbe(router, "/ready")
+}
+
+func TestReadyAndHealthy(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      Status
+		drain       bool
+		wantReady   bool
+		wantHealthy bool
+	}{
+		{"healthy", StatusHealthy, false, true, true},
+		{"degraded", StatusDegraded, false, true, true},
+		{"unhealthy", StatusUnhealthy, false, false, false},
+		{"draining", StatusHealthy, true, false, true},
+		{"draining and unhealthy", StatusUnhealthy, true, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler()
+			h.AddChecker(staticChecker{status: tt.status})
+			probeReady(h)
+			if tt.drain {
+				h.Drain()
+				if code := probeReady(h); code != http.StatusServiceUnavailable {
+					t.Fatalf("draining probe = %d, want 503", code)
+				}
+			}
+
+			if h.Ready() != tt.wantReady {
+				t.Errorf("Ready() = %v, want %v", h.Ready(), tt.wantReady)
+			}
+			if h.Healthy() != tt.wantHealthy {
+				t.Errorf("Healthy() = %v, want %v", h.Healthy(), tt.wantHealthy)
+			}
+		})
+	}
+}
+
+func TestReadyAndHealthyBeforeFirstProbe(t *testing.T) {
+	h := NewHandler()
+	if !h.Ready() || !h.Healthy() {
+		t.Errorf("Ready() = %v, Healthy() = %v before any probe, want both true", h.Ready(), h.Healthy())
+	}
+}
+
+// serveProbe sends GET path to router and returns the status code
+func serveProbe(router *gin.Engine, path string) int {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w.Code
+}
+
+func TestRegisterRoutesWithPaths(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	h := NewHandler()
+	h.MarkStarted()
+	router := gin.New()
+	h.RegisterRoutesWithPaths(router, "/healthz", "/readyz")
+
+	for _, path := range []string{"/healthz", "/readyz", "/started"} {
+		if code := serveProbe(router, path); code != http.StatusOK {
+			t.Errorf("%s status = %d, want 200", path, code)
+		}
+	}
+	for _, path := range []string{"/health", "/ready"} {
+		if code := serveProbe(router, path); code != http.StatusNotFound {
+			t.Errorf("%s status = %d, want 404 with custom paths", path, code)
+		}
+	}
+}
+
+func TestRegisterRoutesDefaults(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	NewHandler().RegisterRoutes(router)
+
+	for _, path := range []string{"/health", "/ready"} {
+		if code := serveProbe(router, path); code != http.StatusOK {
+			t.Errorf("%s status = %d, want 200", path, code)
+		}
+	}
+	if code := serveProbe(router, "/started"); code != http.StatusServiceUnavailable {
+		t.Errorf("/started before MarkStarted = %d, want 503", code)
+	}
+}
@@ -0,0 +1,113 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPChecker checks that a downstream HTTP service responds
+type HTTPChecker struct {
+	name    string
+	url     string
+	path    string
+	timeout time.Duration
+	client  *http.Client
+}
+
+// NewHTTPChecker creates a checker that GETs url and reports healthy for 2xx,
+// degraded for other statuses and timeouts, and unhealthy when the service
+// cannot be reached. A timeout of 0 relies on the context deadline alone.
+func NewHTTPChecker(name, url string, timeout time.Duration) *HTTPChecker {
+	return &HTTPChecker{
+		name:    name,
+		url:     strings.TrimRight(url, "/"),
+		timeout: timeout,
+		client:  http.DefaultClient,
+	}
+}
+
+// WithPath sets the path appended to the URL, e.g. "/health"
+func (h *HTTPChecker) WithPath(path string) *HTTPChecker {
+	h.path = "/" + strings.TrimLeft(path, "/")
+	return h
+}
+
+// WithClient sets the HTTP client used for the check
+func (h *HTTPChecker) WithClient(client *http.Client) *HTTPChecker {
+	if client != nil {
+		h.client = client
+	}
+	return h
+}
+
+// Name returns the checker name
+func (h *HTTPChecker) Name() string {
+	return h.name
+}
+
+// Check performs the HTTP health check
+func (h *HTTPChecker) Check(ctx context.Context) ComponentCheck {
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.url+h.path, nil)
+	if err != nil {
+		return ComponentCheck{
+			Status:  StatusUnhealthy,
+			Message: "Invalid health check request: " + err.Error(),
+		}
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		if isTimeout(err) {
+			return ComponentCheck{
+				Status:  StatusDegraded,
+				Message: "Request timed out",
+				Latency: time.Since(start).String(),
+			}
+		}
+		return ComponentCheck{
+			Status:  StatusUnhealthy,
+			Message: "Request failed: " + err.Error(),
+		}
+	}
+	defer resp.Body.Close()
+	// Drain so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	latency := time.Since(start).String()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return ComponentCheck{
+			Status:  StatusDegraded,
+			Message: fmt.Sprintf("Health check returned status %d", resp.StatusCode),
+			Latency: latency,
+		}
+	}
+
+	return ComponentCheck{
+		Status:  StatusHealthy,
+		Message: fmt.Sprintf("Responded %d", resp.StatusCode),
+		Latency: latency,
+	}
+}
+
+// isTimeout reports whether err is a deadline or network timeout
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,48 @@
+package health
+
+import (
+	"context"
+	"time"
+)
+
+// retryChecker retries an unhealthy check before reporting it
+type retryChecker struct {
+	checker  Checker
+	attempts int
+	backoff  time.Duration
+}
+
+// WithRetry wraps checker so an unhealthy result is retried up to attempts times,
+// waiting backoff between tries, so transient blips don't flap readiness.
+// The first non-unhealthy result is returned; retries stop at the context deadline.
+func WithRetry(checker Checker, attempts int, backoff time.Duration) Checker {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return &retryChecker{checker: checker, attempts: attempts, backoff: backoff}
+}
+
+// Name returns the wrapped checker's name
+func (r *retryChecker) Name() string {
+	return r.checker.Name()
+}
+
+// Check runs the wrapped check with retries
+func (r *retryChecker) Check(ctx context.Context) ComponentCheck {
+	var check ComponentCheck
+	for attempt := 1; attempt <= r.attempts; attempt++ {
+		check = r.checker.Check(ctx)
+		if check.Status != StatusUnhealthy || attempt == r.attempts {
+			return check
+		}
+
+		timer := time.NewTimer(r.backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return check
+		case <-timer.C:
+		}
+	}
+	return check
+}
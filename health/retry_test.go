@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// flakyChecker fails its first failures checks, then reports healthy
+type flakyChecker struct {
+	failures int
+	calls    int
+}
+
+func (f *flakyChecker) Name() string { return "flaky" }
+
+func (f *flakyChecker) Check(ctx context.Context) ComponentCheck {
+	f.calls++
+	if f.calls <= f.failures {
+		return ComponentCheck{Status: StatusUnhealthy, Message: "connection reset"}
+	}
+	return ComponentCheck{Status: StatusHealthy}
+}
+
+func TestWithRetryRecoversFromTransientFailures(t *testing.T) {
+	flaky := &flakyChecker{failures: 2}
+	checker := WithRetry(flaky, 3, time.Millisecond)
+
+	if check := checker.Check(context.Background()); check.Status != StatusHealthy {
+		t.Fatalf("status = %s, want healthy after two failures", check.Status)
+	}
+	if flaky.calls != 3 {
+		t.Errorf("calls = %d, want 3", flaky.calls)
+	}
+	if checker.Name() != "flaky" {
+		t.Errorf("Name() = %q, want the wrapped name", checker.Name())
+	}
+}
+
+func TestWithRetryReportsUnhealthyAfterAttempts(t *testing.T) {
+	flaky := &flakyChecker{failures: 5}
+	check := WithRetry(flaky, 3, time.Millisecond).Check(context.Background())
+
+	if check.Status != StatusUnhealthy || check.Message != "connection reset" {
+		t.Errorf("check = %+v, want the last unhealthy result", check)
+	}
+	if flaky.calls != 3 {
+		t.Errorf("calls = %d, want 3", flaky.calls)
+	}
+}
+
+func TestWithRetryStopsAtDeadline(t *testing.T) {
+	flaky := &flakyChecker{failures: 5}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	check := WithRetry(flaky, 5, time.Second).Check(ctx)
+
+	if check.Status != StatusUnhealthy {
+		t.Errorf("status = %s, want unhealthy", check.Status)
+	}
+	if flaky.calls != 1 {
+		t.Errorf("calls = %d, want 1 before the deadline", flaky.calls)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("took %v, want to stop at the deadline", elapsed)
+	}
+}
+
+func TestWithRetrySucceedsWithoutRetrying(t *testing.T) {
+	flaky := &flakyChecker{}
+	WithRetry(flaky, 0, time.Millisecond).Check(context.Background())
+	if flaky.calls != 1 {
+		t.Errorf("calls = %d, want 1", flaky.calls)
+	}
+}
@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SQLQueryChecker checks the database by running a query and validating its result
+type SQLQueryChecker struct {
+	db       *gorm.DB
+	name     string
+	query    string
+	validate func(rows *sql.Rows) error
+}
+
+// NewSQLQueryChecker creates a checker that runs query and passes the rows to
+// validate, catching logical failures such as a missing table or replica lag.
+// validate may be nil to only require the query to succeed.
+func NewSQLQueryChecker(db *gorm.DB, name, query string, validate func(rows *sql.Rows) error) *SQLQueryChecker {
+	return &SQLQueryChecker{db: db, name: name, query: query, validate: validate}
+}
+
+// Name returns the checker name
+func (s *SQLQueryChecker) Name() string {
+	return s.name
+}
+
+// Check performs the query health check
+func (s *SQLQueryChecker) Check(ctx context.Context) ComponentCheck {
+	start := time.Now()
+
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return ComponentCheck{
+			Status:  StatusUnhealthy,
+			Message: "Failed to get database connection: " + err.Error(),
+		}
+	}
+
+	rows, err := sqlDB.QueryContext(ctx, s.query)
+	if err != nil {
+		return ComponentCheck{
+			Status:  StatusUnhealthy,
+			Message: "Query failed: " + err.Error(),
+		}
+	}
+	defer rows.Close()
+
+	if s.validate != nil {
+		if err := s.validate(rows); err != nil {
+			return ComponentCheck{
+				Status:  StatusUnhealthy,
+				Message: "Query validation failed: " + err.Error(),
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return ComponentCheck{
+			Status:  StatusUnhealthy,
+			Message: "Query failed: " + err.Error(),
+		}
+	}
+
+	return ComponentCheck{
+		Status:  StatusHealthy,
+		Message: "Query OK",
+		Latency: time.Since(start).String(),
+	}
+}
@@ -0,0 +1,81 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB opens an in-memory SQLite database
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func TestSQLQueryChecker(t *testing.T) {
+	db := openTestDB(t)
+	if err := db.Exec("CREATE TABLE replication (lag_seconds INTEGER)").Error; err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	if err := db.Exec("INSERT INTO replication VALUES (3)").Error; err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	lagBelow := func(max int) func(rows *sql.Rows) error {
+		return func(rows *sql.Rows) error {
+			if !rows.Next() {
+				return errors.New("no rows")
+			}
+			var lag int
+			if err := rows.Scan(&lag); err != nil {
+				return err
+			}
+			if lag > max {
+				return errors.New("replica lag too high")
+			}
+			return nil
+		}
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		validate    func(rows *sql.Rows) error
+		wantStatus  Status
+		wantMessage string
+	}{
+		{"query only", "SELECT 1", nil, StatusHealthy, "Query OK"},
+		{"validation passes", "SELECT lag_seconds FROM replication", lagBelow(5), StatusHealthy, "Query OK"},
+		{"validation fails", "SELECT lag_seconds FROM replication", lagBelow(1), StatusUnhealthy, "Query validation failed: replica lag too high"},
+		{"missing table", "SELECT * FROM missing_table", nil, StatusUnhealthy, "Query failed:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewSQLQueryChecker(db, "replica", tt.query, tt.validate)
+			if checker.Name() != "replica" {
+				t.Errorf("name = %q, want replica", checker.Name())
+			}
+
+			check := checker.Check(context.Background())
+			if check.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", check.Status, tt.wantStatus, check.Message)
+			}
+			if !strings.HasPrefix(check.Message, tt.wantMessage) {
+				t.Errorf("message = %q, want prefix %q", check.Message, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package health
+
+import (
+	"context"
+	"time"
+)
+
+// timeoutChecker bounds a check with its own deadline
+type timeoutChecker struct {
+	checker Checker
+	timeout time.Duration
+}
+
+// AddCheckerWithTimeout adds a health checker that must finish within timeout.
+// A check that runs over reports unhealthy with "check timed out" instead of
+// holding up the readiness response, even if it ignores its context.
+func (h *Handler) AddCheckerWithTimeout(checker Checker, timeout time.Duration) {
+	h.AddChecker(&timeoutChecker{checker: checker, timeout: timeout})
+}
+
+// Name returns the wrapped checker's name
+func (t *timeoutChecker) Name() string {
+	return t.checker.Name()
+}
+
+// Check runs the wrapped check under a deadline derived from ctx
+func (t *timeoutChecker) Check(ctx context.Context) ComponentCheck {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	start := time.Now()
+	result := make(chan ComponentCheck, 1)
+	go func() {
+		result <- t.checker.Check(ctx)
+	}()
+
+	select {
+	case check := <-result:
+		return check
+	case <-ctx.Done():
+		return ComponentCheck{
+			Status:  StatusUnhealthy,
+			Message: "check timed out",
+			Latency: time.Since(start).String(),
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Defaults used when OutputPath selects an HTTP collector
+const (
+	defaultHTTPBatchSize     = 100
+	defaultHTTPFlushInterval = time.Second
+)
+
+// maxBufferedBatches bounds how many batches Write buffers while the collector
+// is slow; lines beyond that are dropped
+const maxBufferedBatches = 10
+
+// HTTPSyncer is a zapcore.WriteSyncer that POSTs encoded log lines in batches
+// as newline-delimited JSON to a collector such as Loki or Vector
+type HTTPSyncer struct {
+	endpoint  string
+	batchSize int
+	client    *http.Client
+
+	mu      sync.Mutex
+	lines   [][]byte
+	dropped uint64
+	sendMu  sync.Mutex    // keeps batches in order
+	flush   chan struct{} // signals flushLoop that a batch is full
+	done    chan struct{}
+	stopped sync.Once
+}
+
+// NewHTTPSyncer creates a syncer that sends a batch once batchSize lines are
+// buffered or every flushInterval, whichever comes first. Call Stop when done.
+func NewHTTPSyncer(endpoint string, batchSize int, flushInterval time.Duration) *HTTPSyncer {
+	if batchSize <= 0 {
+		batchSize = defaultHTTPBatchSize
+	}
+	if flushInterval <= 0 {
+		flushInterval = defaultHTTPFlushInterval
+	}
+
+	s := &HTTPSyncer{
+		endpoint:  endpoint,
+		batchSize: batchSize,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		flush:     make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+	go s.flushLoop(flushInterval)
+	return s
+}
+
+// Write buffers one encoded entry and signals the flush loop once a batch is
+// full. It never blocks on the collector; lines are dropped while
+// maxBufferedBatches batches are already waiting.
+func (s *HTTPSyncer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	if len(s.lines) >= s.batchSize*maxBufferedBatches {
+		s.dropped++
+		s.mu.Unlock()
+		return len(p), nil
+	}
+	// zap reuses the buffer after Write returns
+	line := make([]byte, len(p))
+	copy(line, p)
+	s.lines = append(s.lines, line)
+	full := len(s.lines) >= s.batchSize
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.flush <- struct{}{}:
+		default:
+			// A flush is already pending
+		}
+	}
+	return len(p), nil
+}
+
+// Sync sends all buffered lines in batches of at most batchSize, returning
+// the first error. zap.Logger.Sync calls it, so syncing the logger flushes.
+func (s *HTTPSyncer) Sync() error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	s.mu.Lock()
+	lines := s.lines
+	s.lines = nil
+	s.mu.Unlock()
+
+	var first error
+	for len(lines) > 0 {
+		n := min(len(lines), s.batchSize)
+		if err := s.send(lines[:n]); err != nil && first == nil {
+			first = err
+		}
+		lines = lines[n:]
+	}
+	return first
+}
+
+// Dropped returns how many lines Write discarded because the buffer was full
+func (s *HTTPSyncer) Dropped() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// Stop stops the interval flush and sends any remaining lines
+func (s *HTTPSyncer) Stop() error {
+	s.stopped.Do(func() { close(s.done) })
+	return s.Sync()
+}
+
+// flushLoop sends buffered lines every interval, or as soon as Write reports
+// a full batch, until Stop
+func (s *HTTPSyncer) flushLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			_ = s.Sync()
+		case <-s.flush:
+			_ = s.Sync()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// send POSTs lines as one request. Failed batches are dropped so a collector
+// outage cannot grow memory without bound.
+func (s *HTTPSyncer) send(lines [][]byte) error {
+	var body bytes.Buffer
+	for _, line := range lines {
+		body.Write(line)
+		if !bytes.HasSuffix(line, []byte("\n")) {
+			body.WriteByte('\n')
+		}
+	}
+
+	resp, err := s.client.Post(s.endpoint, "application/x-ndjson", &body)
+	if err != nil {
+		return fmt.Errorf("logger: failed to send %d lines: %w", len(lines), err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("logger: collector returned status %d for %d lines", resp.StatusCode, len(lines))
+	}
+	return nil
+}
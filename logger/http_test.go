@@ -0,0 +1,173 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collector records the batches POSTed to it, one slice of lines per request
+type collector struct {
+	mu      sync.Mutex
+	batches [][]string
+	block   chan struct{} // when set, requests wait for it to close
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if c.block != nil {
+		<-c.block
+	}
+	body, _ := io.ReadAll(r.Body)
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	c.mu.Lock()
+	c.batches = append(c.batches, lines)
+	c.mu.Unlock()
+}
+
+// received returns the batches received so far
+func (c *collector) received() [][]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]string(nil), c.batches...)
+}
+
+// waitForBatches waits until at least n batches arrived, failing after timeout
+func (c *collector) waitForBatches(t *testing.T, n int, timeout time.Duration) [][]string {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if batches := c.received(); len(batches) >= n {
+			return batches
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("got %d batches, want %d", len(c.received()), n)
+	return nil
+}
+
+// startCollector starts a test collector server
+func startCollector(t *testing.T) (*collector, *httptest.Server) {
+	t.Helper()
+	c := &collector{}
+	server := httptest.NewServer(c)
+	t.Cleanup(server.Close)
+	return c, server
+}
+
+func TestHTTPSyncerSendsFullBatches(t *testing.T) {
+	c, server := startCollector(t)
+	syncer := NewHTTPSyncer(server.URL, 3, time.Hour)
+	defer syncer.Stop()
+
+	for _, line := range []string{"a", "b", "c", "d", "e", "f"} {
+		if _, err := syncer.Write([]byte(`{"msg":"` + line + `"}` + "\n")); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	batches := c.waitForBatches(t, 2, time.Second)
+	var lines []string
+	for _, batch := range batches {
+		if len(batch) != 3 {
+			t.Errorf("batch = %v, want 3 lines", batch)
+		}
+		lines = append(lines, batch...)
+	}
+	if got := strings.Join(lines, ""); got != `{"msg":"a"}{"msg":"b"}{"msg":"c"}{"msg":"d"}{"msg":"e"}{"msg":"f"}` {
+		t.Errorf("delivered %s, want every line in order", got)
+	}
+}
+
+func TestHTTPSyncerFlushesOnInterval(t *testing.T) {
+	c, server := startCollector(t)
+	syncer := NewHTTPSyncer(server.URL, 100, 20*time.Millisecond)
+	defer syncer.Stop()
+
+	syncer.Write([]byte(`{"msg":"lonely"}`))
+
+	batches := c.waitForBatches(t, 1, time.Second)
+	if len(batches[0]) != 1 || batches[0][0] != `{"msg":"lonely"}` {
+		t.Errorf("batch = %v, want the single line newline-terminated", batches[0])
+	}
+}
+
+func TestHTTPSyncerWriteDoesNotBlockOnCollector(t *testing.T) {
+	c, server := startCollector(t)
+	c.block = make(chan struct{})
+	syncer := NewHTTPSyncer(server.URL, 2, time.Hour)
+	defer func() {
+		close(c.block)
+		syncer.Stop()
+	}()
+
+	total := 2*maxBufferedBatches + 50
+	start := time.Now()
+	for i := 0; i < total; i++ {
+		syncer.Write([]byte("line\n"))
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("writes took %v with a stalled collector, want non-blocking", elapsed)
+	}
+	if syncer.Dropped() == 0 {
+		t.Error("Dropped() = 0, want lines dropped once the buffer is full")
+	}
+}
+
+func TestHTTPSyncerStopSendsRemainingLines(t *testing.T) {
+	c, server := startCollector(t)
+	syncer := NewHTTPSyncer(server.URL, 100, time.Hour)
+
+	syncer.Write([]byte("one\n"))
+	syncer.Write([]byte("two\n"))
+	if err := syncer.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if batches := c.received(); len(batches) != 1 || len(batches[0]) != 2 {
+		t.Errorf("batches after Stop = %v, want one batch of 2", batches)
+	}
+	if err := syncer.Stop(); err != nil {
+		t.Errorf("second Stop: %v", err)
+	}
+}
+
+func TestHTTPSyncerReportsCollectorErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	syncer := NewHTTPSyncer(server.URL, 100, time.Hour)
+	defer syncer.Stop()
+
+	syncer.Write([]byte("line\n"))
+	if err := syncer.Sync(); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Sync() = %v, want the collector status", err)
+	}
+}
+
+func TestNewWithHTTPOutputFlushesOnSync(t *testing.T) {
+	c, server := startCollector(t)
+	logger, err := New(Config{Level: "info", OutputPath: server.URL, Format: "json"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	logger.Info("shipped")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	batches := c.received()
+	if len(batches) != 1 || len(batches[0]) != 1 || !strings.Contains(batches[0][0], `"msg":"shipped"`) {
+		t.Errorf("batches after Sync = %v, want the logged entry", batches)
+	}
+}
@@ -12,8 +12,11 @@ import (
 // Config holds logger configuration
 type Config struct {
 	Level      string // debug, info, warn, error
-	OutputPath string // stdout, stderr, or file path
+	OutputPath string // stdout, stderr, http(s):// collector URL, or file path
 	Format     string // json, console
+
+	// LevelOverrides sets levels for loggers created with Named, e.g. {"gorm": "warn"}
+	LevelOverrides map[string]string
 }
 
 // DefaultConfig returns default logger configuration
@@ -25,7 +28,9 @@ func DefaultConfig() Config {
 	}
 }
 
-// New creates a new zap logger with the given configuration
+// New creates a new zap logger with the given configuration. With an
+// http(s):// OutputPath lines are sent in batches, so call Sync on the
+// returned logger before exiting to deliver the last batch.
 func New(cfg Config) (*zap.Logger, error) {
 	// Parse log level
 	level := parseLevel(cfg.Level)
@@ -47,11 +52,14 @@ func New(cfg Config) (*zap.Logger, error) {
 
 	// Create output writer
 	var output zapcore.WriteSyncer
-	switch strings.ToLower(cfg.OutputPath) {
-	case "stdout", "":
+	outputPath := strings.ToLower(cfg.OutputPath)
+	switch {
+	case outputPath == "stdout" || outputPath == "":
 		output = zapcore.AddSync(os.Stdout)
-	case "stderr":
+	case outputPath == "stderr":
 		output = zapcore.AddSync(os.Stderr)
+	case strings.HasPrefix(outputPath, "http://") || strings.HasPrefix(outputPath, "https://"):
+		output = NewHTTPSyncer(cfg.OutputPath, defaultHTTPBatchSize, defaultHTTPFlushInterval)
 	default:
 		file, err := os.OpenFile(cfg.OutputPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 		if err != nil {
@@ -61,7 +69,14 @@ func New(cfg Config) (*zap.Logger, error) {
 	}
 
 	// Create core
-	core := zapcore.NewCore(encoder, output, level)
+	var core zapcore.Core = zapcore.NewCore(encoder, output, level)
+	if len(cfg.LevelOverrides) > 0 {
+		overrides := make(map[string]zapcore.Level, len(cfg.LevelOverrides))
+		for name, lvl := range cfg.LevelOverrides {
+			overrides[name] = parseLevel(lvl)
+		}
+		core = &overrideCore{Core: core, encoder: encoder, output: output, overrides: overrides}
+	}
 
 	// Create logger with options
 	logger := zap.New(core,
@@ -94,6 +109,11 @@ func NewProduction() (*zap.Logger, error) {
 func FromEnv() (*zap.Logger, error) {
 	cfg := DefaultConfig()
 
+	// Keep test output clean unless LOG_LEVEL asks otherwise
+	if os.Getenv("ENV") == "test" {
+		cfg.Level = "error"
+	}
+
 	if level := os.Getenv("LOG_LEVEL"); level != "" {
 		cfg.Level = level
 	}
@@ -103,6 +123,15 @@ func FromEnv() (*zap.Logger, error) {
 	if format := os.Getenv("LOG_FORMAT"); format != "" {
 		cfg.Format = format
 	}
+	// LOG_LEVEL_OVERRIDES format: name=level,name=level
+	if overrides := os.Getenv("LOG_LEVEL_OVERRIDES"); overrides != "" {
+		cfg.LevelOverrides = make(map[string]string)
+		for _, pair := range strings.Split(overrides, ",") {
+			if name, lvl, ok := strings.Cut(pair, "="); ok {
+				cfg.LevelOverrides[strings.TrimSpace(name)] = strings.TrimSpace(lvl)
+			}
+		}
+	}
 
 	// Use console format for dev environment
 	if env := os.Getenv("ENV"); env == "dev" || env == "development" {
@@ -137,3 +166,63 @@ func WithService(logger *zap.Logger, serviceName string) *zap.Logger {
 func WithRequestID(logger *zap.Logger, requestID string) *zap.Logger {
 	return logger.With(zap.String("request_id", requestID))
 }
+
+// WithTraceID adds trace ID to logger
+func WithTraceID(logger *zap.Logger, traceID string) *zap.Logger {
+	return logger.With(zap.String("trace_id", traceID))
+}
+
+// Named returns a child logger with the given name. If the logger was created
+// with a LevelOverrides entry for name, the child logs at that level instead of
+// the base level.
+func Named(logger *zap.Logger, name string) *zap.Logger {
+	named := logger.Named(name)
+
+	core, ok := logger.Core().(*overrideCore)
+	if !ok {
+		return named
+	}
+	level, ok := core.overrides[name]
+	if !ok {
+		return named
+	}
+
+	return named.WithOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core {
+		return &overrideCore{
+			Core:      zapcore.NewCore(core.encoder.Clone(), core.output, level),
+			encoder:   core.encoder,
+			output:    core.output,
+			overrides: core.overrides,
+		}
+	}))
+}
+
+// overrideCore keeps what is needed to rebuild the core at another level for Named
+type overrideCore struct {
+	zapcore.Core
+	encoder   zapcore.Encoder
+	output    zapcore.WriteSyncer
+	overrides map[string]zapcore.Level
+}
+
+// With adds fields to both the core and the encoder used for rebuilt cores
+func (c *overrideCore) With(fields []zapcore.Field) zapcore.Core {
+	encoder := c.encoder.Clone()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+	return &overrideCore{
+		Core:      c.Core.With(fields),
+		encoder:   encoder,
+		output:    c.output,
+		overrides: c.overrides,
+	}
+}
+
+// Check adds this core, not the embedded one, so entries go through the wrapper
+func (c *overrideCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
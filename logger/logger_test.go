@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// clearLogEnv unsets the environment variables FromEnv reads
+func clearLogEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"ENV", "LOG_LEVEL", "LOG_OUTPUT", "LOG_FORMAT", "LOG_LEVEL_OVERRIDES"} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestFromEnvSilencesInfoInTestEnv(t *testing.T) {
+	clearLogEnv(t)
+	t.Setenv("ENV", "test")
+
+	logger, err := FromEnv()
+	if err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+	if logger.Core().Enabled(zapcore.InfoLevel) {
+		t.Error("info enabled with ENV=test")
+	}
+	if !logger.Core().Enabled(zapcore.ErrorLevel) {
+		t.Error("error disabled with ENV=test")
+	}
+}
+
+func TestFromEnvExplicitLevelOverridesTestEnv(t *testing.T) {
+	clearLogEnv(t)
+	t.Setenv("ENV", "test")
+	t.Setenv("LOG_LEVEL", "debug")
+
+	logger, err := FromEnv()
+	if err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+	if !logger.Core().Enabled(zapcore.DebugLevel) {
+		t.Error("debug disabled despite LOG_LEVEL=debug")
+	}
+}
+
+func TestFromEnvDefaultsToInfo(t *testing.T) {
+	clearLogEnv(t)
+
+	logger, err := FromEnv()
+	if err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+	if !logger.Core().Enabled(zapcore.InfoLevel) || logger.Core().Enabled(zapcore.DebugLevel) {
+		t.Error("default level should be info")
+	}
+}
+
+func TestNamedHonorsLevelOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	root, err := New(Config{
+		Level:          "info",
+		OutputPath:     path,
+		Format:         "json",
+		LevelOverrides: map[string]string{"gorm": "warn", "worker": "debug"},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	gorm := Named(root, "gorm")
+	worker := Named(root, "worker")
+	other := Named(root, "http")
+
+	root.Info("root info")
+	root.Debug("root debug")
+	gorm.Info("gorm info")
+	gorm.Warn("gorm warn")
+	worker.Debug("worker debug")
+	other.Info("http info")
+	other.Debug("http debug")
+	_ = root.Sync()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	logged := string(content)
+	for _, msg := range []string{"root info", "gorm warn", "worker debug", "http info"} {
+		if !strings.Contains(logged, `"`+msg+`"`) {
+			t.Errorf("%q not logged", msg)
+		}
+	}
+	for _, msg := range []string{"root debug", "gorm info", "http debug"} {
+		if strings.Contains(logged, `"`+msg+`"`) {
+			t.Errorf("%q logged below its level", msg)
+		}
+	}
+	if !strings.Contains(logged, `"logger":"gorm"`) {
+		t.Error("named logger entries missing the logger name")
+	}
+}
+
+func TestNamedWithoutOverridesKeepsBaseLevel(t *testing.T) {
+	root, err := New(Config{Level: "warn", OutputPath: filepath.Join(t.TempDir(), "app.log")})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if Named(root, "gorm").Core().Enabled(zapcore.InfoLevel) {
+		t.Error("named logger without an override enabled info under a warn base")
+	}
+}
@@ -0,0 +1,93 @@
+// Package metrics provides common Prometheus collectors for all services.
+//
+// Collectors are registered on prometheus.DefaultRegisterer, the same registry
+// used by the middleware package, so a single /metrics handler exposes both.
+package metrics
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+var (
+	buildInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "app_build_info",
+			Help: "Build information of the running service, always 1",
+		},
+		[]string{"version", "commit", "go_version"},
+	)
+
+	buildInfoOnce sync.Once
+
+	cacheRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "app_cache_requests_total",
+			Help: "Cache lookups by cache name and result (hit or miss)",
+		},
+		[]string{"cache", "result"},
+	)
+
+	cacheRequestsOnce sync.Once
+)
+
+// RegisterBuildInfo registers the app_build_info gauge labeled with the given
+// version and commit. Calling it again replaces the previous labels.
+func RegisterBuildInfo(version, commit string) {
+	buildInfoOnce.Do(func() {
+		prometheus.MustRegister(buildInfo)
+	})
+
+	buildInfo.Reset()
+	buildInfo.WithLabelValues(version, commit, runtime.Version()).Set(1)
+}
+
+// RegisterRuntimeMetrics registers the Go runtime (go_goroutines, heap, GC) and
+// process collectors. It is safe to call more than once and when the collectors
+// are already registered, as they are on the unmodified default registry.
+func RegisterRuntimeMetrics() {
+	for _, c := range []prometheus.Collector{
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	} {
+		if err := prometheus.Register(c); err != nil {
+			var are prometheus.AlreadyRegisteredError
+			if !errors.As(err, &are) {
+				panic(err)
+			}
+		}
+	}
+}
+
+// CacheMetrics counts hits and misses of one cache
+type CacheMetrics struct {
+	hits   prometheus.Counter
+	misses prometheus.Counter
+}
+
+// CacheCounter returns hit/miss counters for the named cache, recorded in
+// app_cache_requests_total{cache,result}. Calls with the same name share series.
+func CacheCounter(name string) *CacheMetrics {
+	cacheRequestsOnce.Do(func() {
+		prometheus.MustRegister(cacheRequests)
+	})
+
+	return &CacheMetrics{
+		hits:   cacheRequests.WithLabelValues(name, "hit"),
+		misses: cacheRequests.WithLabelValues(name, "miss"),
+	}
+}
+
+// Hit records a cache hit
+func (c *CacheMetrics) Hit() {
+	c.hits.Inc()
+}
+
+// Miss records a cache miss
+func (c *CacheMetrics) Miss() {
+	c.misses.Inc()
+}
@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// gather returns the metric family called name from the default registry
+func gather(t *testing.T, name string) *dto.MetricFamily {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() == name {
+			return family
+		}
+	}
+	return nil
+}
+
+// labels returns the label pairs of m as a map
+func labels(m *dto.Metric) map[string]string {
+	result := make(map[string]string, len(m.GetLabel()))
+	for _, pair := range m.GetLabel() {
+		result[pair.GetName()] = pair.GetValue()
+	}
+	return result
+}
+
+func TestRegisterBuildInfo(t *testing.T) {
+	RegisterBuildInfo("1.2.3", "abc123")
+	RegisterBuildInfo("1.2.4", "def456")
+
+	family := gather(t, "app_build_info")
+	if family == nil {
+		t.Fatal("app_build_info not registered")
+	}
+	if len(family.GetMetric()) != 1 {
+		t.Fatalf("got %d series, want 1 after re-registering", len(family.GetMetric()))
+	}
+
+	sample := family.GetMetric()[0]
+	want := map[string]string{"version": "1.2.4", "commit": "def456", "go_version": runtime.Version()}
+	got := labels(sample)
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("label %s = %q, want %q", name, got[name], value)
+		}
+	}
+	if value := sample.GetGauge().GetValue(); value != 1 {
+		t.Errorf("value = %v, want 1", value)
+	}
+}
+
+func TestRegisterRuntimeMetrics(t *testing.T) {
+	RegisterRuntimeMetrics()
+	RegisterRuntimeMetrics()
+
+	server := httptest.NewServer(promhttp.Handler())
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("scrape: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read scrape: %v", err)
+	}
+
+	for _, name := range []string{"go_goroutines", "go_memstats_heap_alloc_bytes", "go_gc_duration_seconds"} {
+		if !strings.Contains(string(body), "\n"+name+" ") && !strings.Contains(string(body), "\n"+name+"{") {
+			t.Errorf("%s missing from the scrape", name)
+		}
+	}
+}
+
+// cacheCount returns the app_cache_requests_total value for cache and result
+func cacheCount(t *testing.T, cache, result string) float64 {
+	t.Helper()
+	family := gather(t, "app_cache_requests_total")
+	if family == nil {
+		return 0
+	}
+	for _, m := range family.GetMetric() {
+		if l := labels(m); l["cache"] == cache && l["result"] == result {
+			return m.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestCacheCounter(t *testing.T) {
+	users := CacheCounter("users")
+	users.Hit()
+	users.Hit()
+	users.Miss()
+	// A second counter for the same cache shares its series
+	CacheCounter("users").Hit()
+	CacheCounter("sessions").Miss()
+
+	tests := []struct {
+		cache, result string
+		want          float64
+	}{
+		{"users", "hit", 3},
+		{"users", "miss", 1},
+		{"sessions", "hit", 0},
+		{"sessions", "miss", 1},
+	}
+	for _, tt := range tests {
+		if got := cacheCount(t, tt.cache, tt.result); got != tt.want {
+			t.Errorf("app_cache_requests_total{cache=%q,result=%q} = %v, want %v", tt.cache, tt.result, got, tt.want)
+		}
+	}
+}
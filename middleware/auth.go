@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// UserIDKey is the gin context key JWTAuth stores the token subject under
+	UserIDKey = "user_id"
+	// ClaimsKey is the gin context key JWTAuth stores the token claims under
+	ClaimsKey = "claims"
+)
+
+// authOptions holds the settings AuthOption functions modify
+type authOptions struct {
+	skipPaths map[string]bool
+	cookie    string
+	secrets   []string
+}
+
+// AuthOption configures JWTAuth and JWTAuthOptional
+type AuthOption func(*authOptions)
+
+// newAuthOptions applies opts to the default settings
+func newAuthOptions(opts []AuthOption) *authOptions {
+	options := &authOptions{skipPaths: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// WithSkipPaths lets requests to the given paths through without a token
+func WithSkipPaths(paths ...string) AuthOption {
+	return func(o *authOptions) {
+		for _, path := range paths {
+			o.skipPaths[path] = true
+		}
+	}
+}
+
+// WithTokenCookie reads the token from the named cookie when the request has
+// no Authorization header
+func WithTokenCookie(name string) AuthOption {
+	return func(o *authOptions) {
+		o.cookie = name
+	}
+}
+
+// WithVerificationSecrets also accepts tokens signed with the given secrets,
+// e.g. JWTConfig.Secrets while rotating keys
+func WithVerificationSecrets(secrets ...string) AuthOption {
+	return func(o *authOptions) {
+		o.secrets = append(o.secrets, secrets...)
+	}
+}
+
+// JWTAuth returns a middleware that requires an HS256 bearer token signed with
+// secret and carrying an unexpired exp claim. The sub claim (or a user_id claim)
+// is stored under UserIDKey and all claims under ClaimsKey. Invalid or missing
+// tokens get 401.
+func JWTAuth(secret string, opts ...AuthOption) gin.HandlerFunc {
+	options := newAuthOptions(opts)
+	secrets := append([]string{secret}, options.secrets...)
+
+	return func(c *gin.Context) {
+		if options.skipPaths[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		token := bearerToken(c, options.cookie)
+		if token == "" {
+			response.Unauthorized(c, "Missing authentication token")
+			c.Abort()
+			return
+		}
+
+		claims, ok := parseToken(token, secrets)
+		if !ok {
+			response.Unauthorized(c, "Invalid or expired token")
+			c.Abort()
+			return
+		}
+
+		setClaims(c, claims)
+		c.Next()
+	}
+}
+
+// JWTAuthOptional returns a middleware that authenticates like JWTAuth when a
+// valid token is present but lets anonymous requests and requests with an
+// invalid token through unauthenticated. Handlers check UserIDKey to branch.
+func JWTAuthOptional(secret string, opts ...AuthOption) gin.HandlerFunc {
+	options := newAuthOptions(opts)
+	secrets := append([]string{secret}, options.secrets...)
+
+	return func(c *gin.Context) {
+		if token := bearerToken(c, options.cookie); token != "" {
+			if claims, ok := parseToken(token, secrets); ok {
+				setClaims(c, claims)
+			}
+		}
+		c.Next()
+	}
+}
+
+// ClaimsFromContext returns the token claims stored by JWTAuth
+func ClaimsFromContext(c *gin.Context) (jwt.MapClaims, bool) {
+	claims, ok := c.Get(ClaimsKey)
+	if !ok {
+		return nil, false
+	}
+	mapClaims, ok := claims.(jwt.MapClaims)
+	return mapClaims, ok
+}
+
+// setClaims stores the token subject and claims in the gin context
+func setClaims(c *gin.Context, claims jwt.MapClaims) {
+	if userID := subject(claims); userID != "" {
+		c.Set(UserIDKey, userID)
+	}
+	c.Set(ClaimsKey, claims)
+}
+
+// bearerToken returns the token from the Authorization header, falling back to cookie if set
+func bearerToken(c *gin.Context, cookie string) string {
+	if header := c.GetHeader("Authorization"); header != "" {
+		scheme, token, found := strings.Cut(header, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") {
+			return ""
+		}
+		return strings.TrimSpace(token)
+	}
+	if cookie != "" {
+		if token, err := c.Cookie(cookie); err == nil {
+			return token
+		}
+	}
+	return ""
+}
+
+// parseToken validates token against each secret in turn and returns its claims
+func parseToken(token string, secrets []string) (jwt.MapClaims, bool) {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		claims := jwt.MapClaims{}
+		parsed, err := parser.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+			return []byte(secret), nil
+		})
+		if err == nil && parsed.Valid {
+			return claims, true
+		}
+		// Only a signature mismatch can succeed with another secret
+		if !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// subject returns the user the token was issued for
+func subject(claims jwt.MapClaims) string {
+	if sub, err := claims.GetSubject(); err == nil && sub != "" {
+		return sub
+	}
+	if userID, ok := claims["user_id"].(string); ok {
+		return userID
+	}
+	return ""
+}
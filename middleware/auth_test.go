@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/config"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signToken returns an HS256 token for sub signed with secret, expiring after ttl
+func signToken(t *testing.T, secret, sub string, ttl time.Duration) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": sub,
+		"exp": time.Now().Add(ttl).Unix(),
+	}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
+
+// authRouter returns a router with auth on /me, which echoes the user ID
+func authRouter(auth gin.HandlerFunc) *gin.Engine {
+	router := gin.New()
+	router.Use(auth)
+	router.GET("/me", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString(UserIDKey))
+	})
+	return router
+}
+
+// getWithToken sends GET /me with token as a bearer token, if set
+func getWithToken(router *gin.Engine, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestJWTAuthAcceptsPreviousSecretDuringRotation(t *testing.T) {
+	cfg := config.JWTConfig{Secret: "current-secret", Secrets: []string{"previous-secret"}}
+	router := authRouter(JWTAuth(cfg.SigningSecret(), WithVerificationSecrets(cfg.VerificationSecrets()...)))
+
+	old := signToken(t, "previous-secret", "user-1", time.Hour)
+	if w := getWithToken(router, old); w.Code != http.StatusOK || w.Body.String() != "user-1" {
+		t.Errorf("token signed with the previous secret: got %d %q, want 200 user-1", w.Code, w.Body.String())
+	}
+
+	if cfg.SigningSecret() != "current-secret" {
+		t.Fatalf("SigningSecret() = %q, want the current secret", cfg.SigningSecret())
+	}
+	fresh := signToken(t, cfg.SigningSecret(), "user-2", time.Hour)
+	if w := getWithToken(router, fresh); w.Code != http.StatusOK || w.Body.String() != "user-2" {
+		t.Errorf("token signed with the current secret: got %d %q, want 200 user-2", w.Code, w.Body.String())
+	}
+
+	unknown := signToken(t, "retired-secret", "user-3", time.Hour)
+	if w := getWithToken(router, unknown); w.Code != http.StatusUnauthorized {
+		t.Errorf("token signed with an unknown secret: got %d, want 401", w.Code)
+	}
+}
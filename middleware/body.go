@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestBodyKey is the context key for the request body captured by CaptureRequestBody
+const RequestBodyKey = "request_body"
+
+// CaptureRequestBody returns a middleware that keeps a copy of up to maxBytes of
+// the request body in the context so it can be logged if the handler panics.
+// The handler still reads the complete, unmodified body.
+func CaptureRequestBody(maxBytes int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if body := c.Request.Body; body != nil && body != http.NoBody {
+			prefix, _ := io.ReadAll(io.LimitReader(body, int64(maxBytes)))
+			c.Request.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(prefix), body), body}
+			c.Set(RequestBodyKey, prefix)
+		}
+		c.Next()
+	}
+}
+
+// capturedBody returns the request body captured by CaptureRequestBody
+func capturedBody(c *gin.Context) ([]byte, bool) {
+	if value, exists := c.Get(RequestBodyKey); exists {
+		if body, ok := value.([]byte); ok {
+			return body, true
+		}
+	}
+	return nil, false
+}
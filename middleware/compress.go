@@ -0,0 +1,250 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// CompressConfig holds response compression configuration
+type CompressConfig struct {
+	Level        int  // gzip level, 0 means gzip.DefaultCompression
+	MinSize      int  // responses smaller than this many bytes are sent uncompressed
+	EnableBrotli bool // prefer br when the client accepts it
+
+	// ExcludedContentTypes are Content-Type prefixes never compressed, such as
+	// already compressed media and event streams
+	ExcludedContentTypes []string
+}
+
+// DefaultCompressConfig returns default compression configuration
+func DefaultCompressConfig() CompressConfig {
+	return CompressConfig{
+		Level:   gzip.DefaultCompression,
+		MinSize: 1024,
+		ExcludedContentTypes: []string{
+			"image/", "video/", "audio/", "font/woff",
+			"application/zip", "application/gzip", "application/x-gzip",
+			"application/pdf", "application/octet-stream", "text/event-stream",
+		},
+	}
+}
+
+// Compress returns a middleware that gzips (or brotli-compresses) responses of
+// at least MinSize bytes for clients that accept it, setting Content-Encoding
+// and Vary: Accept-Encoding. Responses that already have a Content-Encoding or
+// an excluded Content-Type pass through, as do responses that are flushed
+// before reaching MinSize. Middleware registered before Compress sees the
+// compressed byte count in c.Writer.Size().
+func Compress(cfg CompressConfig) gin.HandlerFunc {
+	if cfg.Level == 0 {
+		cfg.Level = gzip.DefaultCompression
+	}
+	if cfg.MinSize < 0 {
+		cfg.MinSize = 0
+	}
+
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"), cfg.EnableBrotli)
+		if encoding == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		cw := &compressWriter{ResponseWriter: original, cfg: cfg, encoding: encoding, status: http.StatusOK}
+		c.Writer = cw
+		defer func() {
+			cw.close()
+			c.Writer = original
+		}()
+
+		c.Next()
+	}
+}
+
+// negotiateEncoding picks br or gzip from an Accept-Encoding header, or "" for
+// neither. A "*" entry only applies to codings the header does not list, so
+// "gzip;q=0, *" still refuses gzip.
+func negotiateEncoding(header string, brotliEnabled bool) string {
+	var gzipQ, brQ, anyQ float64
+	var gzipListed, brListed bool
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "gzip":
+			gzipQ, gzipListed = q, true
+		case "br":
+			brQ, brListed = q, true
+		case "*":
+			anyQ = q
+		}
+	}
+	if !gzipListed {
+		gzipQ = anyQ
+	}
+	if !brListed {
+		brQ = anyQ
+	}
+
+	switch {
+	case brotliEnabled && brQ > 0 && brQ >= gzipQ:
+		return "br"
+	case gzipQ > 0:
+		return "gzip"
+	default:
+		return ""
+	}
+}
+
+// compressWriter holds back the response until MinSize bytes are written,
+// then either compresses it or passes it through unchanged
+type compressWriter struct {
+	gin.ResponseWriter
+	cfg      CompressConfig
+	encoding string
+
+	status  int
+	pending bytes.Buffer
+	decided bool
+	encoder io.WriteCloser // nil when passing through
+}
+
+// WriteHeader records the status until the encoding is decided
+func (w *compressWriter) WriteHeader(code int) {
+	if code > 0 && !w.decided {
+		w.status = code
+	}
+}
+
+// WriteHeaderNow is deferred until the encoding is decided
+func (w *compressWriter) WriteHeaderNow() {}
+
+// Write buffers data until the encoding is decided, then writes it through the encoder
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.pending.Write(data)
+		if w.pending.Len() < w.cfg.MinSize {
+			return len(data), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+	if w.encoder != nil {
+		return w.encoder.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString buffers or writes s like Write
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Status returns the response status
+func (w *compressWriter) Status() int {
+	if w.decided {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+// Written reports whether the handler has written a header or body
+func (w *compressWriter) Written() bool {
+	return w.decided || w.pending.Len() > 0 || w.ResponseWriter.Written()
+}
+
+// Flush sends what has been written so far, passing the response through
+// uncompressed if it has not reached MinSize
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if flusher, ok := w.encoder.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide sends the header and buffered body, compressing if allowed and eligible
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+	header := w.ResponseWriter.Header()
+	if compress && w.eligible(header) {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		if w.encoding == "br" {
+			w.encoder = brotli.NewWriter(w.ResponseWriter)
+		} else {
+			encoder, err := gzip.NewWriterLevel(w.ResponseWriter, w.cfg.Level)
+			if err != nil {
+				encoder = gzip.NewWriter(w.ResponseWriter)
+			}
+			w.encoder = encoder
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.pending.Len() == 0 {
+		w.ResponseWriter.WriteHeaderNow()
+		return nil
+	}
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(w.pending.Bytes())
+	} else {
+		_, err = w.ResponseWriter.Write(w.pending.Bytes())
+	}
+	w.pending.Reset()
+	return err
+}
+
+// eligible reports whether the response may be compressed
+func (w *compressWriter) eligible(header http.Header) bool {
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	if w.status < http.StatusOK || w.status == http.StatusNoContent || w.status == http.StatusNotModified {
+		return false
+	}
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	for _, excluded := range w.cfg.ExcludedContentTypes {
+		if strings.HasPrefix(contentType, excluded) {
+			return false
+		}
+	}
+	return true
+}
+
+// close sends a response still below MinSize uncompressed and finishes the encoder
+func (w *compressWriter) close() {
+	if !w.decided {
+		if !w.Written() {
+			// Nothing was written; leave the status to gin
+			if w.status != http.StatusOK {
+				w.ResponseWriter.WriteHeader(w.status)
+			}
+			return
+		}
+		_ = w.decide(w.pending.Len() >= w.cfg.MinSize)
+	}
+	if w.encoder != nil {
+		_ = w.encoder.Close()
+	}
+}
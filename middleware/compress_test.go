@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		brotli bool
+		want   string
+	}{
+		{"", true, ""},
+		{"gzip", false, "gzip"},
+		{"gzip, br", true, "br"},
+		{"gzip, br", false, "gzip"},
+		{"gzip;q=1.0, br;q=0.5", true, "gzip"},
+		{"br;q=0, gzip", true, "gzip"},
+		{"gzip;q=0", false, ""},
+		{"identity", false, ""},
+		{"*", false, "gzip"},
+		{"gzip;q=0, *", false, ""},
+		{"*, gzip;q=0", false, ""},
+		{"br;q=0, *", true, "gzip"},
+		{"*;q=0", true, ""},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header, tt.brotli); got != tt.want {
+			t.Errorf("negotiateEncoding(%q, brotli %v) = %q, want %q", tt.header, tt.brotli, got, tt.want)
+		}
+	}
+}
+
+// compressRouter returns a router compressing with cfg whose /json serves a
+// large JSON body, /small a short one and /image a large PNG body. size
+// receives c.Writer.Size() as seen by middleware registered before Compress.
+func compressRouter(cfg CompressConfig, size *int) *gin.Engine {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Next()
+		*size = c.Writer.Size()
+	})
+	router.Use(Compress(cfg))
+	router.GET("/json", func(c *gin.Context) {
+		c.String(http.StatusOK, strings.Repeat(`{"name":"board"},`, 200))
+	})
+	router.GET("/small", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+	router.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", []byte(strings.Repeat("x", 4096)))
+	})
+	return router
+}
+
+// getEncoded sends GET path with Accept-Encoding set to acceptEncoding
+func getEncoded(router *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCompressGzip(t *testing.T) {
+	var size int
+	w := getEncoded(compressRouter(DefaultCompressConfig(), &size), "/json", "gzip")
+
+	if w.Header().Get("Content-Encoding") != "gzip" || w.Header().Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("Content-Encoding %q, Vary %q, want gzip and Accept-Encoding", w.Header().Get("Content-Encoding"), w.Header().Get("Vary"))
+	}
+	if size != w.Body.Len() {
+		t.Errorf("c.Writer.Size() = %d, want the compressed byte count %d", size, w.Body.Len())
+	}
+	reader, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("invalid gzip body: %v", err)
+	}
+	body, _ := io.ReadAll(reader)
+	if want := strings.Repeat(`{"name":"board"},`, 200); string(body) != want {
+		t.Errorf("decompressed %d bytes, want the %d byte body", len(body), len(want))
+	}
+}
+
+func TestCompressBrotli(t *testing.T) {
+	cfg := DefaultCompressConfig()
+	cfg.EnableBrotli = true
+	var size int
+	w := getEncoded(compressRouter(cfg, &size), "/json", "gzip, br")
+
+	if w.Header().Get("Content-Encoding") != "br" {
+		t.Fatalf("Content-Encoding = %q, want br", w.Header().Get("Content-Encoding"))
+	}
+	body, err := io.ReadAll(brotli.NewReader(w.Body))
+	if err != nil || string(body) != strings.Repeat(`{"name":"board"},`, 200) {
+		t.Errorf("invalid brotli body: %v", err)
+	}
+}
+
+func TestCompressPassesThrough(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		want           string
+	}{
+		{"below MinSize", "/small", "gzip", "ok"},
+		{"excluded content type", "/image", "gzip", strings.Repeat("x", 4096)},
+		{"not accepted", "/small", "identity", "ok"},
+		{"gzip refused despite wildcard", "/json", "gzip;q=0, *", strings.Repeat(`{"name":"board"},`, 200)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var size int
+			w := getEncoded(compressRouter(DefaultCompressConfig(), &size), tt.path, tt.acceptEncoding)
+
+			if got := w.Header().Get("Content-Encoding"); got != "" {
+				t.Errorf("Content-Encoding = %q, want none", got)
+			}
+			if w.Header().Get("Vary") != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", w.Header().Get("Vary"))
+			}
+			if w.Body.String() != tt.want || size != len(tt.want) {
+				t.Errorf("body of %d bytes, size %d, want the %d byte body unchanged", w.Body.Len(), size, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestCompressKeepsStatusAndExistingEncoding(t *testing.T) {
+	router := gin.New()
+	router.Use(Compress(CompressConfig{MinSize: 1}))
+	router.GET("/created", func(c *gin.Context) { c.String(http.StatusCreated, "created") })
+	router.GET("/encoded", func(c *gin.Context) {
+		c.Header("Content-Encoding", "deflate")
+		c.String(http.StatusOK, "already encoded")
+	})
+	router.GET("/empty", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	if w := getEncoded(router, "/created", "gzip"); w.Code != http.StatusCreated || w.Header().Get("Content-Encoding") != "gzip" {
+		t.Errorf("/created: status %d, Content-Encoding %q, want a gzipped 201", w.Code, w.Header().Get("Content-Encoding"))
+	}
+	if w := getEncoded(router, "/encoded", "gzip"); w.Header().Get("Content-Encoding") != "deflate" || w.Body.String() != "already encoded" {
+		t.Errorf("/encoded: Content-Encoding %q, body %q, want it untouched", w.Header().Get("Content-Encoding"), w.Body.String())
+	}
+	if w := getEncoded(router, "/empty", "gzip"); w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+		t.Errorf("/empty: status %d with %d bytes, want an empty 204", w.Code, w.Body.Len())
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// PerUserConcurrency returns a middleware that caps simultaneous in-flight
+// requests per key, responding 429 once a key already has n requests in progress.
+// keyFunc defaults to the user_id set by auth middleware, falling back to the client IP.
+// It must run after auth middleware for user_id to be available.
+func PerUserConcurrency(n int, keyFunc func(*gin.Context) string) gin.HandlerFunc {
+	if keyFunc == nil {
+		keyFunc = userOrIPKey
+	}
+
+	var mu sync.Mutex
+	inFlight := make(map[string]int)
+
+	release := func(key string) {
+		mu.Lock()
+		defer mu.Unlock()
+		inFlight[key]--
+		if inFlight[key] <= 0 {
+			delete(inFlight, key)
+		}
+	}
+
+	return func(c *gin.Context) {
+		key := keyFunc(c)
+
+		mu.Lock()
+		if inFlight[key] >= n {
+			mu.Unlock()
+			response.TooManyRequests(c, "Too many concurrent requests")
+			c.Abort()
+			return
+		}
+		inFlight[key]++
+		mu.Unlock()
+
+		// Release on completion and on panic
+		defer release(key)
+
+		c.Next()
+	}
+}
+
+// userOrIPKey identifies the caller by user_id if authenticated, otherwise by client IP
+func userOrIPKey(c *gin.Context) string {
+	if userID, exists := c.Get("user_id"); exists {
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return "ip:" + c.ClientIP()
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// concurrencyRouter returns a router capped at n requests per X-User header.
+// /block signals entered and waits for release; /panic panics.
+func concurrencyRouter(n int, entered chan<- string, release <-chan struct{}) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.CustomRecovery(func(c *gin.Context, _ interface{}) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", c.GetHeader("X-User"))
+		c.Next()
+	})
+	router.Use(PerUserConcurrency(n, nil))
+	router.GET("/block", func(c *gin.Context) {
+		entered <- c.GetHeader("X-User")
+		<-release
+		c.Status(http.StatusOK)
+	})
+	router.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+	router.GET("/ok", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+// serve sends a GET to path as user and returns the status code
+func serve(router *gin.Engine, path, user string) int {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("X-User", user)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestPerUserConcurrencyCapsOneUser(t *testing.T) {
+	entered := make(chan string, 10)
+	release := make(chan struct{})
+	router := concurrencyRouter(2, entered, release)
+
+	var wg sync.WaitGroup
+	codes := make(chan int, 3)
+	for _, user := range []string{"alice", "alice", "bob"} {
+		wg.Add(1)
+		go func(user string) {
+			defer wg.Done()
+			codes <- serve(router, "/block", user)
+		}(user)
+	}
+	for i := 0; i < 3; i++ {
+		select {
+		case <-entered:
+		case <-time.After(time.Second):
+			t.Fatal("requests within the cap did not reach the handler")
+		}
+	}
+
+	if code := serve(router, "/block", "alice"); code != http.StatusTooManyRequests {
+		t.Errorf("alice over the cap got %d, want 429", code)
+	}
+
+	close(release)
+	wg.Wait()
+	close(codes)
+	for code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("admitted request got %d, want 200", code)
+		}
+	}
+
+	if code := serve(router, "/ok", "alice"); code != http.StatusOK {
+		t.Errorf("alice after release got %d, want 200", code)
+	}
+}
+
+func TestPerUserConcurrencyReleasesOnPanic(t *testing.T) {
+	router := concurrencyRouter(1, nil, nil)
+
+	for i := 0; i < 3; i++ {
+		if code := serve(router, "/panic", "alice"); code != http.StatusInternalServerError {
+			t.Fatalf("panic request %d got %d, want 500", i, code)
+		}
+	}
+	if code := serve(router, "/ok", "alice"); code != http.StatusOK {
+		t.Errorf("request after panics got %d, want 200", code)
+	}
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -18,14 +20,14 @@ type CORSConfig struct {
 
 // DefaultCORSConfig returns default CORS configuration
 func DefaultCORSConfig() CORSConfig {
-    return CORSConfig{
-        AllowedOrigins:   []string{"*"},
-        AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-        AllowedHeaders:   []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID", "X-Workspace-Id"},  // X-Workspace-Id 추가
-        ExposedHeaders:   []string{"X-Request-ID"},
-        AllowCredentials: true,
-        MaxAge:           86400,
-    }
+	return CORSConfig{
+		AllowedOrigins:   []string{"*"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID", "X-Workspace-Id"}, // X-Workspace-Id 추가
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           86400,
+	}
 }
 
 // CORS returns a middleware that handles CORS
@@ -57,6 +59,9 @@ func CORS(config CORSConfig) gin.HandlerFunc {
 
 		// Handle preflight requests
 		if c.Request.Method == "OPTIONS" {
+			if config.MaxAge > 0 {
+				c.Header("Access-Control-Max-Age", strconv.Itoa(config.MaxAge))
+			}
 			c.AbortWithStatus(204)
 			return
 		}
@@ -74,10 +79,57 @@ func DefaultCORS() gin.HandlerFunc {
 func CORSWithOrigins(origins string) gin.HandlerFunc {
 	config := DefaultCORSConfig()
 	if origins != "" && origins != "*" {
-		config.AllowedOrigins = strings.Split(origins, ",")
-		for i := range config.AllowedOrigins {
-			config.AllowedOrigins[i] = strings.TrimSpace(config.AllowedOrigins[i])
-		}
+		config.AllowedOrigins = splitList(origins)
 	}
 	return CORS(config)
 }
+
+// CORSFromEnv returns CORS middleware configured from environment variables,
+// falling back to DefaultCORSConfig for unset ones:
+// CORS_ALLOWED_ORIGINS, CORS_ALLOWED_METHODS, CORS_ALLOWED_HEADERS and
+// CORS_EXPOSED_HEADERS (comma-separated), CORS_ALLOW_CREDENTIALS (bool)
+// and CORS_MAX_AGE (seconds).
+func CORSFromEnv() gin.HandlerFunc {
+	return CORS(CORSConfigFromEnv())
+}
+
+// CORSConfigFromEnv builds a CORSConfig from environment variables as described in CORSFromEnv
+func CORSConfigFromEnv() CORSConfig {
+	config := DefaultCORSConfig()
+
+	if origins := os.Getenv("CORS_ALLOWED_ORIGINS"); origins != "" {
+		config.AllowedOrigins = splitList(origins)
+	}
+	if methods := os.Getenv("CORS_ALLOWED_METHODS"); methods != "" {
+		config.AllowedMethods = splitList(methods)
+	}
+	if headers := os.Getenv("CORS_ALLOWED_HEADERS"); headers != "" {
+		config.AllowedHeaders = splitList(headers)
+	}
+	if headers := os.Getenv("CORS_EXPOSED_HEADERS"); headers != "" {
+		config.ExposedHeaders = splitList(headers)
+	}
+	if credentials := os.Getenv("CORS_ALLOW_CREDENTIALS"); credentials != "" {
+		if b, err := strconv.ParseBool(credentials); err == nil {
+			config.AllowCredentials = b
+		}
+	}
+	if maxAge := os.Getenv("CORS_MAX_AGE"); maxAge != "" {
+		if n, err := strconv.Atoi(maxAge); err == nil {
+			config.MaxAge = n
+		}
+	}
+
+	return config
+}
+
+// splitList splits a comma-separated value, trimming spaces and dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
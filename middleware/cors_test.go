@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// clearCORSEnv unsets the variables CORSConfigFromEnv reads
+func clearCORSEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{
+		"CORS_ALLOWED_ORIGINS", "CORS_ALLOWED_METHODS", "CORS_ALLOWED_HEADERS",
+		"CORS_EXPOSED_HEADERS", "CORS_ALLOW_CREDENTIALS", "CORS_MAX_AGE",
+	} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestCORSConfigFromEnv(t *testing.T) {
+	clearCORSEnv(t)
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com, https://admin.example.com")
+	t.Setenv("CORS_ALLOWED_METHODS", "GET,POST")
+	t.Setenv("CORS_ALLOWED_HEADERS", "Content-Type, X-Custom")
+	t.Setenv("CORS_EXPOSED_HEADERS", "X-Request-ID,X-Total-Count")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "false")
+	t.Setenv("CORS_MAX_AGE", "600")
+
+	want := CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com", "https://admin.example.com"},
+		AllowedMethods:   []string{"GET", "POST"},
+		AllowedHeaders:   []string{"Content-Type", "X-Custom"},
+		ExposedHeaders:   []string{"X-Request-ID", "X-Total-Count"},
+		AllowCredentials: false,
+		MaxAge:           600,
+	}
+	if got := CORSConfigFromEnv(); !reflect.DeepEqual(got, want) {
+		t.Errorf("CORSConfigFromEnv() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCORSConfigFromEnvDefaults(t *testing.T) {
+	clearCORSEnv(t)
+	t.Setenv("CORS_MAX_AGE", "not-a-number")
+
+	if got := CORSConfigFromEnv(); !reflect.DeepEqual(got, DefaultCORSConfig()) {
+		t.Errorf("CORSConfigFromEnv() = %+v, want defaults", got)
+	}
+}
+
+func TestCORSFromEnvCredentialsHonored(t *testing.T) {
+	for _, credentials := range []string{"true", "false"} {
+		clearCORSEnv(t)
+		t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com")
+		t.Setenv("CORS_ALLOW_CREDENTIALS", credentials)
+
+		router := gin.New()
+		router.Use(CORSFromEnv())
+		router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		got := w.Header().Get("Access-Control-Allow-Credentials") == "true"
+		if got != (credentials == "true") {
+			t.Errorf("CORS_ALLOW_CREDENTIALS=%s: Allow-Credentials sent = %v", credentials, got)
+		}
+		if origin := w.Header().Get("Access-Control-Allow-Origin"); origin != "https://app.example.com" {
+			t.Errorf("Allow-Origin = %q", origin)
+		}
+	}
+}
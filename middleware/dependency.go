@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+)
+
+// dependencyTimingsKey is the request context key for the dependency timings
+type dependencyTimingsKey struct{}
+
+// dependencyTimings accumulates the time a request spent in each named dependency
+type dependencyTimings struct {
+	mu        sync.Mutex
+	durations map[string]time.Duration
+}
+
+// DependencyTracking returns a middleware that records the downstream calls
+// handlers time with StartDependency. Logger emits the total time per
+// dependency as a "dependencies" object.
+func DependencyTracking() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timings := &dependencyTimings{durations: make(map[string]time.Duration)}
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), dependencyTimingsKey{}, timings))
+		c.Next()
+	}
+}
+
+// StartDependency starts timing a call to the named dependency, e.g. "postgres"
+// or "user-service", and returns a func that stops it. Repeated calls to one
+// dependency add up. It does nothing outside DependencyTracking.
+//
+//	defer middleware.StartDependency(ctx, "postgres")()
+func StartDependency(ctx context.Context, name string) func() {
+	timings, ok := ctx.Value(dependencyTimingsKey{}).(*dependencyTimings)
+	if !ok {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		timings.mu.Lock()
+		defer timings.mu.Unlock()
+		timings.durations[name] += elapsed
+	}
+}
+
+// dependencyTimingsFromContext returns the timings recorded for a request, or nil if none
+func dependencyTimingsFromContext(ctx context.Context) *dependencyTimings {
+	timings, _ := ctx.Value(dependencyTimingsKey{}).(*dependencyTimings)
+	if timings == nil {
+		return nil
+	}
+	timings.mu.Lock()
+	defer timings.mu.Unlock()
+	if len(timings.durations) == 0 {
+		return nil
+	}
+	return timings
+}
+
+// MarshalLogObject encodes the durations by dependency name
+func (t *dependencyTimings) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	names := make([]string, 0, len(t.durations))
+	for name := range t.durations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		enc.AddDuration(name, t.durations[name])
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestDependencyTimingsLogged(t *testing.T) {
+	logger, logs := observedLogger()
+	router := gin.New()
+	router.Use(Logger(logger), DependencyTracking())
+	router.GET("/", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		for i := 0; i < 2; i++ {
+			stop := StartDependency(ctx, "postgres")
+			time.Sleep(2 * time.Millisecond)
+			stop()
+		}
+		stop := StartDependency(ctx, "user-service")
+		time.Sleep(3 * time.Millisecond)
+		stop()
+		c.Status(http.StatusOK)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	dependencies, ok := onlyEntry(t, logs).ContextMap()["dependencies"].(map[string]interface{})
+	if !ok || len(dependencies) != 2 {
+		t.Fatalf("dependencies = %v, want postgres and user-service", dependencies)
+	}
+	if postgres, _ := dependencies["postgres"].(time.Duration); postgres < 4*time.Millisecond {
+		t.Errorf("postgres = %v, want both calls added up to at least 4ms", dependencies["postgres"])
+	}
+	if userService, _ := dependencies["user-service"].(time.Duration); userService < 3*time.Millisecond {
+		t.Errorf("user-service = %v, want at least 3ms", dependencies["user-service"])
+	}
+}
+
+func TestDependencyTimingsOmittedWhenUnused(t *testing.T) {
+	logger, logs := observedLogger()
+	router := gin.New()
+	router.Use(Logger(logger), DependencyTracking())
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if _, ok := onlyEntry(t, logs).ContextMap()["dependencies"]; ok {
+		t.Error("dependencies logged although no call was timed")
+	}
+}
+
+func TestStartDependencyWithoutTracking(t *testing.T) {
+	stop := StartDependency(context.Background(), "postgres")
+	stop()
+	if timings := dependencyTimingsFromContext(context.Background()); timings != nil {
+		t.Errorf("timings = %v outside DependencyTracking, want nil", timings)
+	}
+}
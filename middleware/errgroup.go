@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/errgroup"
+)
+
+// ErrGroupLimitKey is the context key for the concurrency limit of request errgroups
+const ErrGroupLimitKey = "errgroup_limit"
+
+// ErrGroup returns a middleware that sets how many subtasks a RequestGroup may
+// run at once. A limit of 0 or less means no limit.
+func ErrGroup(limit int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(ErrGroupLimitKey, limit)
+		c.Next()
+	}
+}
+
+// RequestGroup returns an errgroup bound to the request context for fanning out
+// to downstreams. Subtasks should use the returned context, which is cancelled
+// when the request is cancelled or any subtask fails; Wait returns the first error.
+func RequestGroup(c *gin.Context) (*errgroup.Group, context.Context) {
+	group, ctx := errgroup.WithContext(c.Request.Context())
+	if limit := c.GetInt(ErrGroupLimitKey); limit > 0 {
+		group.SetLimit(limit)
+	}
+	return group, ctx
+}
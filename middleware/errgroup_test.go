@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// groupContext returns a gin context whose request uses ctx, after running ErrGroup(limit)
+func groupContext(ctx context.Context, limit int) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	ErrGroup(limit)(c)
+	return c
+}
+
+func TestRequestGroupReturnsFirstErrorAndCancelsOthers(t *testing.T) {
+	c := groupContext(context.Background(), 0)
+	group, ctx := RequestGroup(c)
+
+	errFirst := errors.New("downstream failed")
+	cancelled := make(chan struct{})
+	group.Go(func() error {
+		select {
+		case <-ctx.Done():
+			close(cancelled)
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+			return nil
+		}
+	})
+	group.Go(func() error { return errFirst })
+
+	if err := group.Wait(); !errors.Is(err, errFirst) {
+		t.Fatalf("Wait() = %v, want the first error", err)
+	}
+	select {
+	case <-cancelled:
+	default:
+		t.Fatal("sibling subtask was not cancelled")
+	}
+}
+
+func TestRequestGroupCancelledWithRequest(t *testing.T) {
+	requestCtx, cancel := context.WithCancel(context.Background())
+	c := groupContext(requestCtx, 0)
+	group, ctx := RequestGroup(c)
+
+	group.Go(func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	cancel()
+
+	if err := group.Wait(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() = %v, want context.Canceled", err)
+	}
+}
+
+func TestRequestGroupLimit(t *testing.T) {
+	c := groupContext(context.Background(), 2)
+	group, _ := RequestGroup(c)
+
+	var running, peak atomic.Int32
+	for i := 0; i < 6; i++ {
+		group.Go(func() error {
+			n := running.Add(1)
+			for {
+				old := peak.Load()
+				if n <= old || peak.CompareAndSwap(old, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		t.Fatalf("Wait() = %v", err)
+	}
+	if peak.Load() > 2 {
+		t.Errorf("%d subtasks ran at once, want at most 2", peak.Load())
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// IfMatchKey is the gin context key RequireIfMatch stores the If-Match header under
+const IfMatchKey = "if_match"
+
+// RequireIfMatch returns a middleware that requires an If-Match header on PUT,
+// PATCH and DELETE requests to the given routes (gin c.FullPath() patterns,
+// all routes when none are given), responding 428 when it is missing. The
+// header is stored under IfMatchKey; handlers compare it with the resource's
+// current ETag using IfMatchMatches and respond 412 on a mismatch.
+func RequireIfMatch(routes ...string) gin.HandlerFunc {
+	routeMap := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		routeMap[route] = true
+	}
+
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			c.Next()
+			return
+		}
+		if len(routeMap) > 0 && !routeMap[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		ifMatch := strings.TrimSpace(c.GetHeader("If-Match"))
+		if ifMatch == "" {
+			response.PreconditionRequired(c, "If-Match header is required")
+			c.Abort()
+			return
+		}
+
+		c.Set(IfMatchKey, ifMatch)
+		c.Next()
+	}
+}
+
+// IfMatchMatches reports whether etag (quoted or not) satisfies the stored
+// If-Match header. "*" matches any ETag and weak ETags never match (RFC 7232).
+func IfMatchMatches(c *gin.Context, etag string) bool {
+	ifMatch := c.GetString(IfMatchKey)
+	if ifMatch == "" {
+		return false
+	}
+	if strings.HasPrefix(etag, "W/") {
+		return false
+	}
+	etag = strings.Trim(etag, `"`)
+
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if !strings.HasPrefix(candidate, "W/") && strings.Trim(candidate, `"`) == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ifMatchRouter returns a router requiring If-Match on /boards/:id whose
+// handlers respond 412 unless the header matches the board's ETag "v2"
+func ifMatchRouter() *gin.Engine {
+	router := gin.New()
+	router.Use(RequireIfMatch("/boards/:id"))
+	handler := func(c *gin.Context) {
+		if _, required := c.Get(IfMatchKey); required && !IfMatchMatches(c, `"v2"`) {
+			c.Status(http.StatusPreconditionFailed)
+			return
+		}
+		c.Status(http.StatusOK)
+	}
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		router.Handle(method, "/boards/:id", handler)
+		router.Handle(method, "/users/:id", handler)
+	}
+	return router
+}
+
+// sendIfMatch sends method to path with If-Match set to ifMatch, if not empty
+func sendIfMatch(router *gin.Engine, method, path, ifMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequireIfMatch(t *testing.T) {
+	router := ifMatchRouter()
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		ifMatch string
+		want    int
+	}{
+		{"PUT without header", http.MethodPut, "/boards/1", "", http.StatusPreconditionRequired},
+		{"PATCH without header", http.MethodPatch, "/boards/1", "", http.StatusPreconditionRequired},
+		{"DELETE without header", http.MethodDelete, "/boards/1", "", http.StatusPreconditionRequired},
+		{"PUT with current ETag", http.MethodPut, "/boards/1", `"v2"`, http.StatusOK},
+		{"PUT with stale ETag", http.MethodPut, "/boards/1", `"v1"`, http.StatusPreconditionFailed},
+		{"GET without header", http.MethodGet, "/boards/1", "", http.StatusOK},
+		{"PUT to another route", http.MethodPut, "/users/1", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := sendIfMatch(router, tt.method, tt.path, tt.ifMatch); w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireIfMatchErrorBody(t *testing.T) {
+	w := sendIfMatch(ifMatchRouter(), http.MethodPut, "/boards/1", "")
+
+	var body struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON %s: %v", w.Body.String(), err)
+	}
+	if body.Error.Code != "PRECONDITION_REQUIRED" {
+		t.Errorf("code = %q, want PRECONDITION_REQUIRED", body.Error.Code)
+	}
+}
+
+func TestRequireIfMatchAllRoutes(t *testing.T) {
+	router := gin.New()
+	router.Use(RequireIfMatch())
+	router.PUT("/users/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	if w := sendIfMatch(router, http.MethodPut, "/users/1", ""); w.Code != http.StatusPreconditionRequired {
+		t.Errorf("status = %d, want 428 with no routes configured", w.Code)
+	}
+}
+
+func TestIfMatchMatches(t *testing.T) {
+	tests := []struct {
+		ifMatch string
+		etag    string
+		want    bool
+	}{
+		{`"v2"`, `"v2"`, true},
+		{`"v2"`, "v2", true},
+		{`"v1", "v2"`, `"v2"`, true},
+		{"*", `"anything"`, true},
+		{`"v1"`, `"v2"`, false},
+		{`W/"v2"`, `"v2"`, false},
+		{`"v2"`, `W/"v2"`, false},
+		{"", `"v2"`, false},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		if tt.ifMatch != "" {
+			c.Set(IfMatchKey, tt.ifMatch)
+		}
+		if got := IfMatchMatches(c, tt.etag); got != tt.want {
+			t.Errorf("IfMatchMatches(%q, %q) = %v, want %v", tt.ifMatch, tt.etag, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// JSONGuard returns a middleware that rejects JSON request bodies larger than
+// maxBytes with 413 and bodies nested deeper than maxDepth with 400, before
+// handlers unmarshal them. The body is restored for the handler. Malformed JSON
+// is left for the handler to report. A limit of 0 disables that check.
+func JSONGuard(maxDepth int, maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body := c.Request.Body
+		if body == nil || body == http.NoBody || !strings.Contains(c.ContentType(), "json") {
+			c.Next()
+			return
+		}
+
+		reader := io.Reader(body)
+		if maxBytes > 0 {
+			reader = io.LimitReader(body, maxBytes+1)
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			response.BadRequest(c, "Failed to read request body")
+			c.Abort()
+			return
+		}
+		if maxBytes > 0 && int64(len(data)) > maxBytes {
+			response.ErrorForStatus(c, http.StatusRequestEntityTooLarge, "Request body too large")
+			c.Abort()
+			return
+		}
+		if maxDepth > 0 && jsonDepthExceeds(data, maxDepth) {
+			response.BadRequest(c, "Request body is nested too deeply")
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(data))
+		c.Next()
+	}
+}
+
+// jsonDepthExceeds reports whether data nests objects or arrays deeper than
+// maxDepth. It streams tokens, so it never builds the nested value.
+func jsonDepthExceeds(data []byte, maxDepth int) bool {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			// io.EOF or a syntax error the handler will report
+			return false
+		}
+		if delim, ok := token.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				depth++
+				if depth > maxDepth {
+					return true
+				}
+			case '}', ']':
+				depth--
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// guardedRouter returns a router with JSONGuard whose /echo handler returns the body it reads
+func guardedRouter(maxDepth int, maxBytes int64) *gin.Engine {
+	router := gin.New()
+	router.Use(JSONGuard(maxDepth, maxBytes))
+	router.POST("/echo", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+	return router
+}
+
+// nested returns JSON arrays nested depth levels deep
+func nested(depth int) string {
+	return strings.Repeat("[", depth) + strings.Repeat("]", depth)
+}
+
+func TestJSONGuard(t *testing.T) {
+	router := guardedRouter(5, 256)
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		want        int
+	}{
+		{"normal payload", `{"name":"alice","tags":["a","b"],"address":{"city":"Seoul"}}`, "application/json", http.StatusOK},
+		{"at the depth limit", nested(5), "application/json", http.StatusOK},
+		{"too deep", nested(6), "application/json", http.StatusBadRequest},
+		{"deep objects", `{"a":{"b":{"c":{"d":{"e":{"f":1}}}}}}`, "application/json; charset=utf-8", http.StatusBadRequest},
+		{"brackets inside strings", `{"s":"[[[[[[[[[["}`, "application/json", http.StatusOK},
+		{"too large", `{"data":"` + strings.Repeat("x", 300) + `"}`, "application/json", http.StatusRequestEntityTooLarge},
+		{"not json", nested(50), "text/plain", http.StatusOK},
+		{"invalid json left to the handler", `{"a":`, "application/json", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if tt.want == http.StatusOK && w.Body.String() != tt.body {
+				t.Errorf("handler read %q, want the restored body", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestJSONGuardZeroDisablesLimits(t *testing.T) {
+	router := guardedRouter(0, 0)
+	body := nested(1000)
+	req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || w.Body.Len() != len(body) {
+		t.Errorf("got %d with %d bytes, want 200 with the full body", w.Code, w.Body.Len())
+	}
+}
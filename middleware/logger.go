@@ -2,28 +2,65 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 // RequestIDKey is the context key for request ID
 const RequestIDKey = "request_id"
 
+// TimeoutReasonKey is the context key a middleware sets, to the reason, when it
+// gives up on a request so Logger can tell timeouts from ordinary errors
+const TimeoutReasonKey = "timeout_reason"
+
+// LoggerConfig holds optional access log features
+type LoggerConfig struct {
+	// ContextKeys lists gin context keys logged under "context" for debugging
+	// middleware ordering, e.g. to verify auth populated what handlers expect
+	ContextKeys []string
+
+	// SuccessSampleRate is the fraction (0-1) of 2xx requests logged, chosen
+	// deterministically from the request ID. 0 disables sampling and logs every
+	// request. Errors and slow requests are always logged.
+	SuccessSampleRate float64
+
+	// SlowThreshold marks requests at least this slow as always logged
+	SlowThreshold time.Duration
+
+	// LogConnection adds the request protocol and a best-effort keep-alive
+	// indicator for debugging connection churn
+	LogConnection bool
+}
+
 // Logger returns a middleware that logs HTTP requests with structured logging
 func Logger(logger *zap.Logger) gin.HandlerFunc {
+	return LoggerWithConfig(logger, LoggerConfig{})
+}
+
+// LoggerWithConfig returns a logger middleware with optional features enabled
+func LoggerWithConfig(logger *zap.Logger, config LoggerConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Generate request ID
-		requestID := uuid.New().String()
-		c.Set(RequestIDKey, requestID)
-		c.Header("X-Request-ID", requestID)
+		// Reuse request ID from RequestContext or the X-Request-ID header, otherwise generate one
+		requestID := ensureRequestID(c)
 
 		// Start timer
 		start := time.Now()
 		path := c.Request.URL.Path
 		query := c.Request.URL.RawQuery
+		// Later middleware such as Timeout replace the request context with one
+		// they cancel themselves, so check the client's context for disconnects
+		requestCtx := c.Request.Context()
 
 		// Process request
 		c.Next()
@@ -34,6 +71,11 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
 		// Get status code
 		statusCode := c.Writer.Status()
 
+		// Sample successful requests that are not slow
+		if statusCode >= 200 && statusCode < 300 && !sampled(config, requestID, duration) {
+			return
+		}
+
 		// Build log fields
 		fields := []zap.Field{
 			zap.String("request_id", requestID),
@@ -52,6 +94,52 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
 			fields = append(fields, zap.Any("user_id", userID))
 		}
 
+		// Add trace and span IDs if the request is traced (e.g. by Tracing)
+		if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.IsValid() {
+			fields = append(fields,
+				zap.String("trace_id", spanContext.TraceID().String()),
+				zap.String("span_id", spanContext.SpanID().String()),
+			)
+		}
+
+		// Add workspace ID if available (from TenantContext)
+		if workspaceID := c.GetString(WorkspaceIDKey); workspaceID != "" {
+			fields = append(fields, zap.String("workspace_id", workspaceID))
+		}
+
+		// Distinguish server-side timeouts from clients that went away
+		if reason := c.GetString(TimeoutReasonKey); reason != "" {
+			fields = append(fields, zap.Bool("timeout", true), zap.String("reason", reason))
+		} else if err := requestCtx.Err(); errors.Is(err, context.DeadlineExceeded) {
+			fields = append(fields, zap.Bool("timeout", true), zap.String("reason", err.Error()))
+		} else if errors.Is(err, context.Canceled) {
+			fields = append(fields, zap.Bool("client_disconnected", true))
+		}
+
+		// Add downstream call timings if tracked (from DependencyTracking)
+		if timings := dependencyTimingsFromContext(c.Request.Context()); timings != nil {
+			fields = append(fields, zap.Object("dependencies", timings))
+		}
+
+		// Add connection info if configured
+		if config.LogConnection {
+			fields = append(fields,
+				zap.String("proto", c.Request.Proto),
+				zap.Bool("keep_alive", keepAlive(c.Request)),
+			)
+		}
+
+		// Add whitelisted context keys if configured
+		if len(config.ContextKeys) > 0 {
+			values := make(map[string]interface{}, len(config.ContextKeys))
+			for _, key := range config.ContextKeys {
+				if value, exists := c.Get(key); exists {
+					values[key] = value
+				}
+			}
+			fields = append(fields, zap.Any("context", values))
+		}
+
 		// Add error if exists
 		if len(c.Errors) > 0 {
 			fields = append(fields, zap.String("error", c.Errors.String()))
@@ -89,6 +177,151 @@ func SkipPathLogger(logger *zap.Logger, skipPaths ...string) gin.HandlerFunc {
 	}
 }
 
+// keepAlive reports whether the connection is expected to be reused after this
+// request. HTTP/2 connections are always multiplexed.
+func keepAlive(r *http.Request) bool {
+	if r.ProtoMajor >= 2 {
+		return true
+	}
+	if r.Close {
+		return false
+	}
+	// HTTP/1.0 only keeps the connection open when asked to
+	if r.ProtoMajor == 1 && r.ProtoMinor == 0 {
+		return strings.EqualFold(r.Header.Get("Connection"), "keep-alive")
+	}
+	return true
+}
+
+// sampled reports whether a successful request should be logged
+func sampled(config LoggerConfig, requestID string, duration time.Duration) bool {
+	if config.SuccessSampleRate <= 0 || config.SuccessSampleRate >= 1 {
+		return true
+	}
+	if config.SlowThreshold > 0 && duration >= config.SlowThreshold {
+		return true
+	}
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(requestID))
+	return float64(mix64(hash.Sum64()))/math.MaxUint64 < config.SuccessSampleRate
+}
+
+// mix64 spreads every input bit over the whole word (the murmur3 finalizer).
+// FNV alone barely changes its high bits for IDs that differ in the last
+// characters, which would sample sequential request IDs all the same way.
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// ResponseTimeHeader is the header RequestContext reports the time to the
+// response headers in, e.g. "12.345ms"
+const ResponseTimeHeader = "X-Response-Time"
+
+// RequestContext returns a middleware that assigns the request ID immediately
+// and sets X-Response-Time just before the response headers are written.
+// Register it first so responses from middleware that abort early (auth,
+// rate limiting) still carry both headers; Logger and response helpers reuse
+// the request ID.
+func RequestContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ensureRequestID(c)
+
+		writer := &timingWriter{ResponseWriter: c.Writer, start: time.Now()}
+		c.Writer = writer
+		defer func() { c.Writer = writer.ResponseWriter }()
+
+		c.Next()
+
+		// gin writes the headers of responses without a body after the
+		// handler chain returns, bypassing this writer
+		writer.setHeader()
+	}
+}
+
+// timingWriter sets ResponseTimeHeader the first time the headers are written
+type timingWriter struct {
+	gin.ResponseWriter
+	start time.Time
+	set   bool
+}
+
+// setHeader sets the response time header unless the headers are already sent
+func (w *timingWriter) setHeader() {
+	if w.set || w.ResponseWriter.Written() {
+		return
+	}
+	w.set = true
+	elapsed := float64(time.Since(w.start)) / float64(time.Millisecond)
+	w.Header().Set(ResponseTimeHeader, strconv.FormatFloat(elapsed, 'f', 3, 64)+"ms")
+}
+
+// WriteHeaderNow sets the response time header and writes the headers
+func (w *timingWriter) WriteHeaderNow() {
+	w.setHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Write sets the response time header and writes data
+func (w *timingWriter) Write(data []byte) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString sets the response time header and writes s
+func (w *timingWriter) WriteString(s string) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Flush sets the response time header and flushes
+func (w *timingWriter) Flush() {
+	w.setHeader()
+	w.ResponseWriter.Flush()
+}
+
+// ensureRequestID returns the request ID in context, otherwise adopts a valid
+// incoming X-Request-ID or generates one, and stores it
+func ensureRequestID(c *gin.Context) string {
+	if requestID, exists := c.Get(RequestIDKey); exists {
+		if id, ok := requestID.(string); ok && id != "" {
+			return id
+		}
+	}
+
+	requestID := c.GetHeader("X-Request-ID")
+	if !validRequestID(requestID) {
+		requestID = uuid.New().String()
+	}
+	c.Set(RequestIDKey, requestID)
+	c.Header("X-Request-ID", requestID)
+	return requestID
+}
+
+// maxRequestIDLength bounds incoming request IDs accepted from clients
+const maxRequestIDLength = 128
+
+// validRequestID reports whether an incoming request ID is safe to log and echo:
+// non-empty, at most maxRequestIDLength bytes of letters, digits and -_.:
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 // GetRequestID gets the request ID from context
 func GetRequestID(c *gin.Context) string {
 	if requestID, exists := c.Get(RequestIDKey); exists {
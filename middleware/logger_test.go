@@ -0,0 +1,291 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// observedLogger returns a logger recording entries at debug and above
+func observedLogger() (*zap.Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	return zap.New(core), logs
+}
+
+// onlyEntry returns the single logged entry, failing if there is not exactly one
+func onlyEntry(t *testing.T, logs *observer.ObservedLogs) observer.LoggedEntry {
+	t.Helper()
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d log entries, want 1", len(entries))
+	}
+	return entries[0]
+}
+
+func TestLoggerContextKeysWhitelist(t *testing.T) {
+	logger, logs := observedLogger()
+	router := gin.New()
+	router.Use(LoggerWithConfig(logger, LoggerConfig{ContextKeys: []string{"user_id", "workspace_id", "absent"}}))
+	router.GET("/", func(c *gin.Context) {
+		c.Set("user_id", "u-1")
+		c.Set("workspace_id", "w-1")
+		c.Set("secret", "do-not-log")
+		c.Status(http.StatusOK)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	fields := onlyEntry(t, logs).ContextMap()
+	values, ok := fields["context"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("context field = %#v, want an object", fields["context"])
+	}
+	if values["user_id"] != "u-1" || values["workspace_id"] != "w-1" {
+		t.Errorf("context = %v, want user_id and workspace_id", values)
+	}
+	if _, ok := values["secret"]; ok {
+		t.Error("non-whitelisted key logged")
+	}
+	if _, ok := values["absent"]; ok {
+		t.Error("unset key logged")
+	}
+}
+
+func TestLoggerOmitsContextByDefault(t *testing.T) {
+	logger, logs := observedLogger()
+	router := gin.New()
+	router.Use(Logger(logger))
+	router.GET("/", func(c *gin.Context) {
+		c.Set("workspace_id", "w-1")
+		c.Status(http.StatusOK)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if _, ok := onlyEntry(t, logs).ContextMap()["context"]; ok {
+		t.Error("context logged without ContextKeys")
+	}
+}
+
+func TestRequestContextHeaderOnEarlyAbort(t *testing.T) {
+	router := gin.New()
+	router.Use(RequestContext())
+	router.Use(func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+	})
+	router.Use(Logger(zap.NewNop()))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", w.Code)
+	}
+	if w.Header().Get("X-Request-ID") == "" {
+		t.Fatal("early-aborted response has no X-Request-ID")
+	}
+}
+
+// responseTime parses the X-Response-Time header of w
+func responseTime(t *testing.T, w *httptest.ResponseRecorder) time.Duration {
+	t.Helper()
+	header := w.Header().Get(ResponseTimeHeader)
+	if header == "" {
+		t.Fatal("response has no X-Response-Time")
+	}
+	d, err := time.ParseDuration(header)
+	if err != nil || d < 0 {
+		t.Fatalf("X-Response-Time = %q, want a non-negative duration", header)
+	}
+	return d
+}
+
+func TestRequestContextResponseTime(t *testing.T) {
+	router := gin.New()
+	router.Use(RequestContext())
+	router.GET("/abort", func(c *gin.Context) { c.AbortWithStatus(http.StatusUnauthorized) })
+	router.GET("/status", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	router.GET("/json", func(c *gin.Context) {
+		time.Sleep(5 * time.Millisecond)
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	for _, path := range []string{"/abort", "/status", "/json", "/missing"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if elapsed := responseTime(t, w); path == "/json" && elapsed < 5*time.Millisecond {
+			t.Errorf("%s: X-Response-Time = %s, want at least the 5ms handler time", path, elapsed)
+		}
+	}
+}
+
+func TestRequestContextResponseTimeOnEarlyAbort(t *testing.T) {
+	router := gin.New()
+	router.Use(RequestContext())
+	router.Use(func(c *gin.Context) {
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "slow down"})
+	})
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", w.Code)
+	}
+	responseTime(t, w)
+	if w.Header().Get("X-Request-ID") == "" {
+		t.Error("early-aborted response has no X-Request-ID")
+	}
+}
+
+func TestRequestContextIDReusedByLogger(t *testing.T) {
+	logger, logs := observedLogger()
+	router := gin.New()
+	router.Use(RequestContext())
+	router.Use(Logger(logger))
+	var handlerID string
+	router.GET("/", func(c *gin.Context) {
+		handlerID = c.GetString(RequestIDKey)
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	headerID := w.Header().Get("X-Request-ID")
+	if headerID == "" || headerID != handlerID {
+		t.Fatalf("header ID %q, handler ID %q, want equal and non-empty", headerID, handlerID)
+	}
+	if logged := onlyEntry(t, logs).ContextMap()["request_id"]; logged != headerID {
+		t.Errorf("logged request_id = %v, want %q", logged, headerID)
+	}
+}
+
+// sampledRouter returns a router whose /ok returns 200 and /fail returns status
+func sampledRouter(logger *zap.Logger, config LoggerConfig, status int) *gin.Engine {
+	router := gin.New()
+	router.Use(LoggerWithConfig(logger, config))
+	router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/fail", func(c *gin.Context) { c.Status(status) })
+	return router
+}
+
+// sendWithIDs sends n requests to path, each with a distinct X-Request-ID
+func sendWithIDs(router *gin.Engine, path string, n int) {
+	for i := 0; i < n; i++ {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-Request-ID", fmt.Sprintf("req-%d", i))
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
+func TestLoggerSamplesSuccessfulRequests(t *testing.T) {
+	logger, logs := observedLogger()
+	router := sampledRouter(logger, LoggerConfig{SuccessSampleRate: 0.1}, http.StatusOK)
+
+	sendWithIDs(router, "/ok", 2000)
+
+	if got := logs.Len(); got < 100 || got > 300 {
+		t.Errorf("logged %d of 2000 successful requests at rate 0.1, want about 200", got)
+	}
+}
+
+func TestLoggerAlwaysLogsErrors(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusInternalServerError} {
+		logger, logs := observedLogger()
+		router := sampledRouter(logger, LoggerConfig{SuccessSampleRate: 0.01}, status)
+
+		sendWithIDs(router, "/fail", 200)
+
+		if got := logs.Len(); got != 200 {
+			t.Errorf("status %d: logged %d of 200 requests, want all", status, got)
+		}
+	}
+}
+
+func TestLoggerAlwaysLogsSlowRequests(t *testing.T) {
+	logger, logs := observedLogger()
+	router := gin.New()
+	router.Use(LoggerWithConfig(logger, LoggerConfig{SuccessSampleRate: 0.01, SlowThreshold: time.Millisecond}))
+	router.GET("/slow", func(c *gin.Context) {
+		time.Sleep(2 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+
+	sendWithIDs(router, "/slow", 20)
+
+	if got := logs.Len(); got != 20 {
+		t.Errorf("logged %d of 20 slow requests, want all", got)
+	}
+}
+
+func TestLoggerSamplingDeterministicPerRequestID(t *testing.T) {
+	config := LoggerConfig{SuccessSampleRate: 0.5}
+	for i := 0; i < 100; i++ {
+		id := fmt.Sprintf("req-%d", i)
+		first := sampled(config, id, 0)
+		for j := 0; j < 5; j++ {
+			if sampled(config, id, 0) != first {
+				t.Fatalf("sampling decision for %s changed between calls", id)
+			}
+		}
+	}
+}
+
+func TestLoggerLogConnection(t *testing.T) {
+	tests := []struct {
+		name      string
+		proto     string
+		major     int
+		minor     int
+		close     bool
+		keepAlive bool
+	}{
+		{"http/1.1", "HTTP/1.1", 1, 1, false, true},
+		{"http/1.1 close", "HTTP/1.1", 1, 1, true, false},
+		{"http/1.0", "HTTP/1.0", 1, 0, false, false},
+		{"http/2", "HTTP/2.0", 2, 0, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, logs := observedLogger()
+			router := gin.New()
+			router.Use(LoggerWithConfig(logger, LoggerConfig{LogConnection: true}))
+			router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Proto, req.ProtoMajor, req.ProtoMinor, req.Close = tt.proto, tt.major, tt.minor, tt.close
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			fields := onlyEntry(t, logs).ContextMap()
+			if fields["proto"] != tt.proto {
+				t.Errorf("proto = %v, want %s", fields["proto"], tt.proto)
+			}
+			if fields["keep_alive"] != tt.keepAlive {
+				t.Errorf("keep_alive = %v, want %v", fields["keep_alive"], tt.keepAlive)
+			}
+		})
+	}
+}
+
+func TestLoggerOmitsConnectionByDefault(t *testing.T) {
+	logger, logs := observedLogger()
+	router := gin.New()
+	router.Use(Logger(logger))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if _, ok := onlyEntry(t, logs).ContextMap()["proto"]; ok {
+		t.Error("proto logged without LogConnection")
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+)
+
+// maskedValue replaces sensitive values in logged bodies
+const maskedValue = "***"
+
+// sensitiveFields are substrings of JSON keys or form fields whose values are masked
+var sensitiveFields = []string{"password", "secret", "token", "authorization", "api_key", "apikey", "card_number", "cvv"}
+
+// maskBody returns body with sensitive JSON or form values masked. Bodies that
+// cannot be parsed (including truncated JSON) are summarized instead of logged.
+func maskBody(body []byte, contentType string) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			break
+		}
+		for key := range values {
+			if isSensitiveField(key) {
+				values[key] = []string{maskedValue}
+			}
+		}
+		return values.Encode()
+	case strings.Contains(mediaType, "json"):
+		var parsed interface{}
+		if err := json.Unmarshal(body, &parsed); err != nil {
+			break
+		}
+		masked, err := json.Marshal(maskJSON(parsed))
+		if err != nil {
+			break
+		}
+		return string(masked)
+	}
+
+	return fmt.Sprintf("[%d bytes of %s omitted]", len(body), contentType)
+}
+
+// maskJSON masks sensitive keys in a decoded JSON value recursively
+func maskJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			if isSensitiveField(key) {
+				v[key] = maskedValue
+			} else {
+				v[key] = maskJSON(nested)
+			}
+		}
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = maskJSON(nested)
+		}
+	}
+	return value
+}
+
+// isSensitiveField reports whether a field name looks like it holds a secret
+func isSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, field := range sensitiveFields {
+		if strings.Contains(name, field) {
+			return true
+		}
+	}
+	return false
+}
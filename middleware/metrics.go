@@ -1,42 +1,121 @@
 package middleware
 
 import (
+	"errors"
+	"fmt"
+	"maps"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/trace"
 )
 
-var (
-	httpRequestsTotal = promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "http_requests_total",
-			Help: "Total number of HTTP requests",
-		},
-		[]string{"method", "path", "status"},
-	)
+// MetricsConfig holds metrics naming and optional features
+type MetricsConfig struct {
+	// Namespace and Subsystem prefix metric names following Prometheus
+	// conventions, e.g. wealist_auth_http_requests_total
+	Namespace string
+	Subsystem string
 
-	httpRequestDuration = promauto.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "http_request_duration_seconds",
-			Help:    "HTTP request duration in seconds",
-			Buckets: prometheus.DefBuckets,
-		},
-		[]string{"method", "path", "status"},
-	)
+	RecordTTFB bool // observe time-to-first-byte in http_ttfb_seconds
 
-	httpRequestsInFlight = promauto.NewGauge(
-		prometheus.GaugeOpts{
-			Name: "http_requests_in_flight",
-			Help: "Current number of HTTP requests being processed",
-		},
-	)
-)
+	// SummaryObjectives enables http_request_duration_summary with these
+	// quantiles (e.g. {0.5: 0.05, 0.9: 0.01, 0.99: 0.001}). Summaries cannot be
+	// aggregated across instances, so this is opt-in. Metrics middleware sharing
+	// a Namespace and Subsystem must use the same objectives.
+	SummaryObjectives map[float64]float64
+
+	// CollapseIDSegments replaces ID-like path segments (numbers, UUIDs and
+	// long hex strings) with ":id" in the path label, for routes registered
+	// with literal IDs
+	CollapseIDSegments bool
+}
+
+// httpMetrics holds the collectors of one metrics middleware configuration
+type httpMetrics struct {
+	requestsTotal    *prometheus.CounterVec
+	requestDuration  *prometheus.HistogramVec
+	requestsInFlight prometheus.Gauge
+	ttfb             *prometheus.HistogramVec
+	durationSummary  *prometheus.SummaryVec
+}
+
+// newHTTPMetrics registers the collectors for config, reusing ones already registered
+func newHTTPMetrics(config MetricsConfig) *httpMetrics {
+	m := &httpMetrics{
+		requestsTotal: registerCollector(prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: config.Namespace,
+				Subsystem: config.Subsystem,
+				Name:      "http_requests_total",
+				Help:      "Total number of HTTP requests",
+			},
+			[]string{"method", "path", "status"},
+		)),
+		requestDuration: registerCollector(prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: config.Namespace,
+				Subsystem: config.Subsystem,
+				Name:      "http_request_duration_seconds",
+				Help:      "HTTP request duration in seconds",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"method", "path", "status"},
+		)),
+		requestsInFlight: registerCollector(prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: config.Namespace,
+				Subsystem: config.Subsystem,
+				Name:      "http_requests_in_flight",
+				Help:      "Current number of HTTP requests being processed",
+			},
+		)),
+	}
+
+	if config.RecordTTFB {
+		m.ttfb = registerCollector(prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: config.Namespace,
+				Subsystem: config.Subsystem,
+				Name:      "http_ttfb_seconds",
+				Help:      "Time from request start to the first response byte in seconds",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"method", "path"},
+		))
+	}
+
+	if len(config.SummaryObjectives) > 0 {
+		m.durationSummary = registerSummary(
+			prometheus.SummaryOpts{
+				Namespace:  config.Namespace,
+				Subsystem:  config.Subsystem,
+				Name:       "http_request_duration_summary",
+				Help:       "HTTP request duration quantiles in seconds",
+				Objectives: config.SummaryObjectives,
+			},
+			[]string{"method", "path"},
+		)
+	}
+
+	return m
+}
 
 // Metrics returns a middleware that collects Prometheus metrics
 func Metrics() gin.HandlerFunc {
+	return MetricsWithConfig(MetricsConfig{})
+}
+
+// MetricsWithConfig returns a metrics middleware with the given naming and features
+func MetricsWithConfig(config MetricsConfig) gin.HandlerFunc {
+	m := newHTTPMetrics(config)
+
 	return func(c *gin.Context) {
 		// Skip metrics endpoint itself
 		if c.Request.URL.Path == "/metrics" {
@@ -44,32 +123,133 @@ func Metrics() gin.HandlerFunc {
 			return
 		}
 
-		httpRequestsInFlight.Inc()
+		m.requestsInFlight.Inc()
 		start := time.Now()
 
+		var ttfb *ttfbWriter
+		if m.ttfb != nil {
+			ttfb = &ttfbWriter{ResponseWriter: c.Writer}
+			c.Writer = ttfb
+		}
+
 		c.Next()
 
-		httpRequestsInFlight.Dec()
+		if ttfb != nil {
+			c.Writer = ttfb.ResponseWriter
+		}
+
+		m.requestsInFlight.Dec()
 		duration := time.Since(start).Seconds()
 		status := strconv.Itoa(c.Writer.Status())
 
 		// Normalize path for metrics (avoid high cardinality)
-		path := normalizePath(c.FullPath())
-		if path == "" {
-			path = c.Request.URL.Path
+		path := normalizePath(c.FullPath(), config.CollapseIDSegments)
+
+		m.requestsTotal.WithLabelValues(c.Request.Method, path, status).Inc()
+		observeWithTrace(c, m.requestDuration.WithLabelValues(c.Request.Method, path, status), duration)
+		if m.durationSummary != nil {
+			m.durationSummary.WithLabelValues(c.Request.Method, path).Observe(duration)
+		}
+
+		if ttfb != nil {
+			// Bodyless responses are only sent after the chain returns
+			firstByte := ttfb.firstByte
+			if firstByte.IsZero() {
+				firstByte = time.Now()
+			}
+			m.ttfb.WithLabelValues(c.Request.Method, path).Observe(firstByte.Sub(start).Seconds())
+		}
+	}
+}
+
+// registerCollector registers collector on the default registry. If an identical
+// collector is already registered it is returned instead, so middleware
+// constructors can safely be called more than once.
+func registerCollector[T prometheus.Collector](collector T) T {
+	if err := prometheus.Register(collector); err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if errors.As(err, &already) {
+			if existing, ok := already.ExistingCollector.(T); ok {
+				return existing
+			}
 		}
+		panic(err)
+	}
+	return collector
+}
+
+var (
+	summaryObjectivesMu sync.Mutex
+	summaryObjectives   = make(map[string]map[float64]float64) // metric name -> objectives
+)
+
+// registerSummary registers a summary like registerCollector. Objectives are not
+// part of a collector's description, so the registry would silently hand back a
+// summary registered with other objectives; that conflict panics instead.
+func registerSummary(opts prometheus.SummaryOpts, labels []string) *prometheus.SummaryVec {
+	name := prometheus.BuildFQName(opts.Namespace, opts.Subsystem, opts.Name)
+
+	summaryObjectivesMu.Lock()
+	defer summaryObjectivesMu.Unlock()
+	if existing, ok := summaryObjectives[name]; ok && !maps.Equal(existing, opts.Objectives) {
+		panic(fmt.Errorf("summary %s already registered with objectives %v, got %v", name, existing, opts.Objectives))
+	}
+
+	summary := registerCollector(prometheus.NewSummaryVec(opts, labels))
+	summaryObjectives[name] = maps.Clone(opts.Objectives)
+	return summary
+}
 
-		httpRequestsTotal.WithLabelValues(c.Request.Method, path, status).Inc()
-		httpRequestDuration.WithLabelValues(c.Request.Method, path, status).Observe(duration)
+// observeWithTrace records value with a trace_id exemplar when the request
+// carries a sampled span, linking the latency sample to its trace
+func observeWithTrace(c *gin.Context, observer prometheus.Observer, value float64) {
+	spanContext := trace.SpanContextFromContext(c.Request.Context())
+	if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok && spanContext.IsSampled() {
+		exemplarObserver.ObserveWithExemplar(value, prometheus.Labels{"trace_id": spanContext.TraceID().String()})
+		return
 	}
+	observer.Observe(value)
 }
 
-// normalizePath normalizes the path to avoid high cardinality in metrics
-func normalizePath(path string) string {
-	if path == "" {
-		return "unknown"
+// unmatchedPath is the path label for requests that matched no route, so
+// scanners probing random URLs cannot create unbounded label values
+const unmatchedPath = "<unmatched>"
+
+// normalizePath returns the route template label, avoiding high cardinality in metrics
+func normalizePath(route string, collapseIDs bool) string {
+	if route == "" {
+		return unmatchedPath
+	}
+	if !collapseIDs {
+		return route
+	}
+
+	segments := strings.Split(route, "/")
+	for i, segment := range segments {
+		if idLike(segment) {
+			segments[i] = ":id"
+		}
 	}
-	return path
+	return strings.Join(segments, "/")
+}
+
+// idLike reports whether a path segment looks like an identifier: all digits,
+// a UUID, or a hex string of at least 16 characters
+func idLike(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	if _, err := uuid.Parse(segment); err == nil && len(segment) == 36 {
+		return true
+	}
+
+	digits, hex := true, len(segment) >= 16
+	for _, r := range segment {
+		isDigit := r >= '0' && r <= '9'
+		digits = digits && isDigit
+		hex = hex && (isDigit || r >= 'a' && r <= 'f' || r >= 'A' && r <= 'F')
+	}
+	return digits || hex
 }
 
 // MetricsWithPrefix returns metrics middleware with custom metric prefix
@@ -102,12 +282,9 @@ func MetricsWithPrefix(prefix string) gin.HandlerFunc {
 
 		duration := time.Since(start).Seconds()
 		status := strconv.Itoa(c.Writer.Status())
-		path := normalizePath(c.FullPath())
-		if path == "" {
-			path = c.Request.URL.Path
-		}
+		path := normalizePath(c.FullPath(), false)
 
 		requestsTotal.WithLabelValues(c.Request.Method, path, status).Inc()
-		requestDuration.WithLabelValues(c.Request.Method, path, status).Observe(duration)
+		observeWithTrace(c, requestDuration.WithLabelValues(c.Request.Method, path, status), duration)
 	}
 }
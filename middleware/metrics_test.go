@@ -0,0 +1,353 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// gatherFamily returns the metric family called name from the default registry
+func gatherFamily(t *testing.T, name string) *dto.MetricFamily {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() == name {
+			return family
+		}
+	}
+	return nil
+}
+
+// findMetric returns the series of family whose labels include want
+func findMetric(family *dto.MetricFamily, want map[string]string) *dto.Metric {
+	if family == nil {
+		return nil
+	}
+	for _, metric := range family.GetMetric() {
+		matched := 0
+		for _, pair := range metric.GetLabel() {
+			if value, ok := want[pair.GetName()]; ok && value == pair.GetValue() {
+				matched++
+			}
+		}
+		if matched == len(want) {
+			return metric
+		}
+	}
+	return nil
+}
+
+func TestMetricsRecordsTTFB(t *testing.T) {
+	delay := 100 * time.Millisecond
+	router := gin.New()
+	router.Use(MetricsWithConfig(MetricsConfig{Namespace: "ttfbtest", RecordTTFB: true}))
+	router.GET("/stream", func(c *gin.Context) {
+		time.Sleep(delay)
+		c.String(http.StatusOK, "first")
+		c.Writer.Flush()
+		time.Sleep(delay)
+		c.String(http.StatusOK, "second")
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/stream", nil))
+
+	metric := findMetric(gatherFamily(t, "ttfbtest_http_ttfb_seconds"), map[string]string{"method": "GET", "path": "/stream"})
+	if metric == nil {
+		t.Fatal("no http_ttfb_seconds sample for /stream")
+	}
+	histogram := metric.GetHistogram()
+	if histogram.GetSampleCount() != 1 {
+		t.Fatalf("sample count = %d, want 1", histogram.GetSampleCount())
+	}
+	ttfb := time.Duration(histogram.GetSampleSum() * float64(time.Second))
+	if ttfb < delay || ttfb >= 2*delay {
+		t.Errorf("ttfb = %v, want between %v and %v", ttfb, delay, 2*delay)
+	}
+
+	total := findMetric(gatherFamily(t, "ttfbtest_http_request_duration_seconds"), map[string]string{"path": "/stream"})
+	if total == nil || total.GetHistogram().GetSampleSum() < (2*delay).Seconds() {
+		t.Errorf("request duration should include both delays")
+	}
+}
+
+func TestMetricsWithoutTTFBRegistersNoHistogram(t *testing.T) {
+	router := gin.New()
+	router.Use(MetricsWithConfig(MetricsConfig{Namespace: "nottfbtest"}))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if family := gatherFamily(t, "nottfbtest_http_ttfb_seconds"); family != nil {
+		t.Fatal("http_ttfb_seconds registered without RecordTTFB")
+	}
+}
+
+// requestWithSpan returns a GET request for target carrying a span context
+func requestWithSpan(target string, sampled bool) (*http.Request, trace.SpanContext) {
+	config := trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	}
+	if sampled {
+		config.TraceFlags = trace.FlagsSampled
+	}
+	spanContext := trace.NewSpanContext(config)
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	return req.WithContext(trace.ContextWithSpanContext(req.Context(), spanContext)), spanContext
+}
+
+// exemplarTraceIDs returns the trace_id labels of the exemplars on a histogram series
+func exemplarTraceIDs(metric *dto.Metric) []string {
+	var ids []string
+	for _, bucket := range metric.GetHistogram().GetBucket() {
+		for _, pair := range bucket.GetExemplar().GetLabel() {
+			if pair.GetName() == "trace_id" {
+				ids = append(ids, pair.GetValue())
+			}
+		}
+	}
+	return ids
+}
+
+func TestMetricsExemplarWithSampledSpan(t *testing.T) {
+	router := gin.New()
+	router.Use(MetricsWithConfig(MetricsConfig{Namespace: "exemplartest"}))
+	router.GET("/traced", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req, spanContext := requestWithSpan("/traced", true)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	metric := findMetric(gatherFamily(t, "exemplartest_http_request_duration_seconds"), map[string]string{"path": "/traced"})
+	if metric == nil {
+		t.Fatal("no duration sample for /traced")
+	}
+	ids := exemplarTraceIDs(metric)
+	if len(ids) != 1 || ids[0] != spanContext.TraceID().String() {
+		t.Errorf("exemplar trace IDs = %v, want [%s]", ids, spanContext.TraceID())
+	}
+}
+
+func TestMetricsNoExemplarWithoutSampledSpan(t *testing.T) {
+	router := gin.New()
+	router.Use(MetricsWithConfig(MetricsConfig{Namespace: "noexemplartest"}))
+	router.GET("/plain", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/plain", nil))
+	unsampled, _ := requestWithSpan("/plain", false)
+	router.ServeHTTP(httptest.NewRecorder(), unsampled)
+
+	metric := findMetric(gatherFamily(t, "noexemplartest_http_request_duration_seconds"), map[string]string{"path": "/plain"})
+	if metric == nil || metric.GetHistogram().GetSampleCount() != 2 {
+		t.Fatal("expected two duration samples for /plain")
+	}
+	if ids := exemplarTraceIDs(metric); len(ids) != 0 {
+		t.Errorf("exemplars recorded without a sampled span: %v", ids)
+	}
+}
+
+func TestMetricsSummaryQuantiles(t *testing.T) {
+	objectives := map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001}
+	router := gin.New()
+	router.Use(MetricsWithConfig(MetricsConfig{Namespace: "summarytest", SummaryObjectives: objectives}))
+	router.GET("/users/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 5; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	}
+
+	metric := findMetric(gatherFamily(t, "summarytest_http_request_duration_summary"), map[string]string{"method": "GET", "path": "/users/:id"})
+	if metric == nil {
+		t.Fatal("no summary series for /users/:id")
+	}
+	summary := metric.GetSummary()
+	if summary.GetSampleCount() != 5 {
+		t.Errorf("sample count = %d, want 5", summary.GetSampleCount())
+	}
+	quantiles := make(map[float64]bool)
+	for _, q := range summary.GetQuantile() {
+		quantiles[q.GetQuantile()] = true
+	}
+	for q := range objectives {
+		if !quantiles[q] {
+			t.Errorf("quantile %v not exposed, got %v", q, quantiles)
+		}
+	}
+}
+
+func TestMetricsSummaryOffByDefault(t *testing.T) {
+	MetricsWithConfig(MetricsConfig{Namespace: "nosummarytest"})
+	if family := gatherFamily(t, "nosummarytest_http_request_duration_summary"); family != nil {
+		t.Fatal("summary registered without SummaryObjectives")
+	}
+}
+
+func TestMetricsSummaryObjectivesConflict(t *testing.T) {
+	config := MetricsConfig{Namespace: "conflicttest", SummaryObjectives: map[float64]float64{0.5: 0.05}}
+	MetricsWithConfig(config)
+	MetricsWithConfig(config) // same objectives are reused
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("registering different objectives did not panic")
+		}
+	}()
+	MetricsWithConfig(MetricsConfig{Namespace: "conflicttest", SummaryObjectives: map[float64]float64{0.99: 0.001}})
+}
+
+func TestMetricsNamespaceAndSubsystem(t *testing.T) {
+	router := gin.New()
+	router.Use(MetricsWithConfig(MetricsConfig{Namespace: "wealist", Subsystem: "auth"}))
+	router.GET("/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/login", nil))
+
+	for _, name := range []string{
+		"wealist_auth_http_requests_total",
+		"wealist_auth_http_request_duration_seconds",
+		"wealist_auth_http_requests_in_flight",
+	} {
+		if gatherFamily(t, name) == nil {
+			t.Errorf("%s not registered", name)
+		}
+	}
+	counter := findMetric(gatherFamily(t, "wealist_auth_http_requests_total"), map[string]string{"method": "GET", "path": "/login", "status": "200"})
+	if counter == nil || counter.GetCounter().GetValue() != 1 {
+		t.Errorf("wealist_auth_http_requests_total{path=/login} = %v, want 1", counter)
+	}
+}
+
+func TestMetricsWithConfigReusesCollectors(t *testing.T) {
+	config := MetricsConfig{Namespace: "reusetest"}
+	first, second := gin.New(), gin.New()
+	first.Use(MetricsWithConfig(config))
+	second.Use(MetricsWithConfig(config))
+	for _, router := range []*gin.Engine{first, second} {
+		router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	counter := findMetric(gatherFamily(t, "reusetest_http_requests_total"), map[string]string{"path": "/"})
+	if counter == nil || counter.GetCounter().GetValue() != 2 {
+		t.Errorf("shared counter = %v, want 2", counter)
+	}
+}
+
+// pathLabels returns the path label values of family
+func pathLabels(family *dto.MetricFamily) []string {
+	var paths []string
+	for _, metric := range family.GetMetric() {
+		for _, pair := range metric.GetLabel() {
+			if pair.GetName() == "path" {
+				paths = append(paths, pair.GetValue())
+			}
+		}
+	}
+	return paths
+}
+
+func TestMetricsPathLabelUsesRouteTemplate(t *testing.T) {
+	router := gin.New()
+	router.Use(MetricsWithConfig(MetricsConfig{Namespace: "pathtest"}))
+	router.GET("/boards/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, target := range []string{"/boards/42", "/boards/5f0c6c3e-8f5c-4f3e-9a49-2d7c3b1f6a10"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+	for _, target := range []string{"/wp-admin/setup.php", "/random/8f14e45fceea167a"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	family := gatherFamily(t, "pathtest_http_requests_total")
+	matched := findMetric(family, map[string]string{"path": "/boards/:id", "status": "200"})
+	if matched == nil || matched.GetCounter().GetValue() != 2 {
+		t.Errorf("path=/boards/:id counter = %v, want 2", matched)
+	}
+	unmatched := findMetric(family, map[string]string{"path": "<unmatched>", "status": "404"})
+	if unmatched == nil || unmatched.GetCounter().GetValue() != 2 {
+		t.Errorf("path=<unmatched> counter = %v, want 2", unmatched)
+	}
+	if paths := pathLabels(family); len(paths) != 2 {
+		t.Errorf("path labels = %v, want only the template and <unmatched>", paths)
+	}
+}
+
+func TestMetricsWithPrefixUnmatchedPath(t *testing.T) {
+	router := gin.New()
+	router.Use(MetricsWithPrefix("prefixpathtest"))
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/123", nil))
+
+	family := gatherFamily(t, "prefixpathtest_http_requests_total")
+	if paths := pathLabels(family); len(paths) != 1 || paths[0] != "<unmatched>" {
+		t.Errorf("path labels = %v, want [<unmatched>]", paths)
+	}
+}
+
+func TestMetricsCollapseIDSegments(t *testing.T) {
+	for _, collapse := range []bool{false, true} {
+		namespace := "collapsetest"
+		if collapse {
+			namespace = "collapsetest_on"
+		}
+		router := gin.New()
+		router.Use(MetricsWithConfig(MetricsConfig{Namespace: namespace, CollapseIDSegments: collapse}))
+		// Routes registered with literal IDs, e.g. generated per tenant
+		for _, route := range []string{
+			"/boards/42/items",
+			"/boards/1337/items",
+			"/files/5f0c6c3e-8f5c-4f3e-9a49-2d7c3b1f6a10",
+			"/commits/8f14e45fceea167a5a36dedd4bea2543",
+			"/users/me",
+		} {
+			router.GET(route, func(c *gin.Context) { c.Status(http.StatusOK) })
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, route, nil))
+		}
+
+		paths := pathLabels(gatherFamily(t, namespace+"_http_requests_total"))
+		slices.Sort(paths)
+		want := []string{
+			"/boards/1337/items",
+			"/boards/42/items",
+			"/commits/8f14e45fceea167a5a36dedd4bea2543",
+			"/files/5f0c6c3e-8f5c-4f3e-9a49-2d7c3b1f6a10",
+			"/users/me",
+		}
+		if collapse {
+			want = []string{"/boards/:id/items", "/commits/:id", "/files/:id", "/users/me"}
+		}
+		if !slices.Equal(paths, want) {
+			t.Errorf("CollapseIDSegments=%v: path labels = %v, want %v", collapse, paths, want)
+		}
+	}
+}
+
+func TestIdLike(t *testing.T) {
+	tests := []struct {
+		segment string
+		want    bool
+	}{
+		{"42", true},
+		{"5f0c6c3e-8f5c-4f3e-9a49-2d7c3b1f6a10", true},
+		{"8f14e45fceea167a", true},
+		{"8f14e45fceea167", false},
+		{"items", false},
+		{":id", false},
+		{"v2", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := idLike(tt.segment); got != tt.want {
+			t.Errorf("idLike(%q) = %v, want %v", tt.segment, got, tt.want)
+		}
+	}
+}
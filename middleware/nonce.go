@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// NonceStore records nonces so replays can be detected
+type NonceStore interface {
+	// Add records nonce for ttl and reports false if it was already recorded
+	Add(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
+
+// NonceGuard returns a middleware that rejects replayed requests: each request
+// must carry a nonce in header, and a nonce seen again within ttl gets 409.
+// Requests without the header get 400. Apply it to sensitive mutating routes.
+// store defaults to an in-memory store, which only protects a single replica.
+func NonceGuard(store NonceStore, header string, ttl time.Duration) gin.HandlerFunc {
+	if store == nil {
+		store = NewMemoryNonceStore()
+	}
+
+	return func(c *gin.Context) {
+		nonce := c.GetHeader(header)
+		if nonce == "" {
+			response.BadRequest(c, "Missing "+header+" header")
+			c.Abort()
+			return
+		}
+
+		fresh, err := store.Add(c.Request.Context(), nonce, ttl)
+		if err != nil {
+			_ = c.Error(err)
+			response.InternalError(c, "Failed to verify request nonce")
+			c.Abort()
+			return
+		}
+		if !fresh {
+			response.Conflict(c, "Request has already been processed")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// MemoryNonceStore is an in-process NonceStore
+type MemoryNonceStore struct {
+	mu        sync.Mutex
+	expires   map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryNonceStore creates an in-memory nonce store
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{expires: make(map[string]time.Time), lastSweep: time.Now()}
+}
+
+// Add records nonce for ttl and reports false if it is still recorded
+func (s *MemoryNonceStore) Add(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	// Drop expired nonces at most once per ttl to keep Add cheap
+	if now.Sub(s.lastSweep) >= ttl {
+		for key, expiry := range s.expires {
+			if now.After(expiry) {
+				delete(s.expires, key)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	if expiry, ok := s.expires[nonce]; ok && now.Before(expiry) {
+		return false, nil
+	}
+	s.expires[nonce] = now.Add(ttl)
+	return true, nil
+}
+
+// RedisNonceStore is a NonceStore shared across replicas through Redis
+type RedisNonceStore struct {
+	setNX func(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// NewRedisNonceStore creates a nonce store backed by Redis SET NX. setNX should
+// set key with the ttl only if it does not exist and report whether it was set,
+// e.g. with go-redis:
+//
+//	func(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+//		return client.SetNX(ctx, key, 1, ttl).Result()
+//	}
+func NewRedisNonceStore(setNX func(ctx context.Context, key string, ttl time.Duration) (bool, error)) *RedisNonceStore {
+	return &RedisNonceStore{setNX: setNX}
+}
+
+// Add records nonce for ttl and reports false if it was already recorded
+func (s *RedisNonceStore) Add(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	return s.setNX(ctx, "nonce:"+nonce, ttl)
+}
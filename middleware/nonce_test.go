@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// nonceRouter returns a router guarding POST /transfer with store
+func nonceRouter(store NonceStore, ttl time.Duration) *gin.Engine {
+	router := gin.New()
+	router.POST("/transfer", NonceGuard(store, "X-Nonce", ttl), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+// postNonce sends POST /transfer with nonce, if not empty
+func postNonce(router *gin.Engine, nonce string) int {
+	req := httptest.NewRequest(http.MethodPost, "/transfer", nil)
+	if nonce != "" {
+		req.Header.Set("X-Nonce", nonce)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestNonceGuardRejectsReplays(t *testing.T) {
+	router := nonceRouter(nil, time.Minute)
+
+	if code := postNonce(router, "n-1"); code != http.StatusOK {
+		t.Fatalf("fresh nonce: status = %d, want 200", code)
+	}
+	if code := postNonce(router, "n-1"); code != http.StatusConflict {
+		t.Errorf("replayed nonce: status = %d, want 409", code)
+	}
+	if code := postNonce(router, "n-2"); code != http.StatusOK {
+		t.Errorf("another fresh nonce: status = %d, want 200", code)
+	}
+	if code := postNonce(router, ""); code != http.StatusBadRequest {
+		t.Errorf("missing nonce: status = %d, want 400", code)
+	}
+}
+
+func TestNonceGuardAcceptsNonceAfterTTL(t *testing.T) {
+	router := nonceRouter(NewMemoryNonceStore(), 20*time.Millisecond)
+
+	postNonce(router, "n-1")
+	time.Sleep(30 * time.Millisecond)
+	if code := postNonce(router, "n-1"); code != http.StatusOK {
+		t.Errorf("nonce after its ttl: status = %d, want 200", code)
+	}
+}
+
+func TestNonceGuardStoreError(t *testing.T) {
+	store := NewRedisNonceStore(func(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+		return false, errors.New("redis unavailable")
+	})
+	if code := postNonce(nonceRouter(store, time.Minute), "n-1"); code != http.StatusInternalServerError {
+		t.Errorf("store error: status = %d, want 500", code)
+	}
+}
+
+func TestRedisNonceStorePrefixesKeys(t *testing.T) {
+	seen := map[string]time.Duration{}
+	store := NewRedisNonceStore(func(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+		_, exists := seen[key]
+		seen[key] = ttl
+		return !exists, nil
+	})
+	router := nonceRouter(store, time.Minute)
+
+	postNonce(router, "n-1")
+	if code := postNonce(router, "n-1"); code != http.StatusConflict {
+		t.Errorf("replayed nonce: status = %d, want 409", code)
+	}
+	if ttl, ok := seen["nonce:n-1"]; !ok || ttl != time.Minute {
+		t.Errorf("SET NX calls = %v, want nonce:n-1 with a 1m ttl", seen)
+	}
+}
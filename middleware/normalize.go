@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// NormalizeErrors returns a middleware that rewraps error responses written by
+// downstream handlers (e.g. third-party libraries) into the standard envelope.
+// Responses below 400 and bodies already in the standard shape pass through untouched.
+func NormalizeErrors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		original := c.Writer
+		buffered := newBufferedWriter(original)
+		c.Writer = buffered
+		defer func() {
+			c.Writer = original
+		}()
+
+		c.Next()
+
+		c.Writer = original
+		body := buffered.body.Bytes()
+		if buffered.status < http.StatusBadRequest || isErrorEnvelope(body) {
+			buffered.flush()
+			return
+		}
+
+		message, details := errorMessage(buffered.status, body)
+		original.Header().Del("Content-Type")
+		original.Header().Del("Content-Length")
+		response.ErrorWithDetails(c, buffered.status, string(response.CodeForStatus(buffered.status)), message, details)
+	}
+}
+
+// isErrorEnvelope reports whether body is already a {error:{code,message},requestId} object
+func isErrorEnvelope(body []byte) bool {
+	var envelope struct {
+		Error *struct {
+			Code    *string `json:"code"`
+			Message *string `json:"message"`
+		} `json:"error"`
+		RequestID      *string `json:"requestId"`
+		RequestIDSnake *string `json:"request_id"` // response.FieldStyleSnake
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return false
+	}
+	return envelope.Error != nil && envelope.Error.Code != nil &&
+		envelope.Error.Message != nil && (envelope.RequestID != nil || envelope.RequestIDSnake != nil)
+}
+
+// errorMessage extracts a human-readable message from a non-standard error body.
+// JSON bodies are also returned as details so no information is lost.
+func errorMessage(status int, body []byte) (string, interface{}) {
+	var parsed interface{}
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		if obj, ok := parsed.(map[string]interface{}); ok {
+			for _, key := range []string{"message", "error", "detail", "title"} {
+				if msg, ok := obj[key].(string); ok && msg != "" {
+					return msg, parsed
+				}
+			}
+		}
+		if msg, ok := parsed.(string); ok && msg != "" {
+			return msg, nil
+		}
+		return http.StatusText(status), parsed
+	}
+
+	if text := strings.TrimSpace(string(body)); text != "" {
+		return text, nil
+	}
+	return http.StatusText(status), nil
+}
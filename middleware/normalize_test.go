@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+func TestNormalizeErrorsRewrapsBareErrorBody(t *testing.T) {
+	router := gin.New()
+	router.Use(NormalizeErrors())
+	router.GET("/lib", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"message": "no such widget"})
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/lib", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	var body response.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body.Error.Code != string(response.CodeNotFound) {
+		t.Errorf("code = %q, want %q", body.Error.Code, response.CodeNotFound)
+	}
+	if body.Error.Message != "no such widget" {
+		t.Errorf("message = %q, want %q", body.Error.Message, "no such widget")
+	}
+	if body.RequestID == "" {
+		t.Error("expected a request ID")
+	}
+}
+
+func TestNormalizeErrorsRewrapsPlainTextBody(t *testing.T) {
+	router := gin.New()
+	router.Use(NormalizeErrors())
+	router.GET("/lib", func(c *gin.Context) {
+		c.String(http.StatusBadGateway, "upstream exploded")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/lib", nil))
+
+	var body response.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON %q: %v", w.Body.String(), err)
+	}
+	if body.Error.Message != "upstream exploded" {
+		t.Errorf("message = %q, want %q", body.Error.Message, "upstream exploded")
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+}
+
+func TestNormalizeErrorsPassesThroughEnvelopeAndSuccess(t *testing.T) {
+	router := gin.New()
+	router.Use(NormalizeErrors())
+	router.GET("/ours", func(c *gin.Context) {
+		c.Set("request_id", "req-1")
+		response.Conflict(c, "taken")
+	})
+	router.GET("/ok", func(c *gin.Context) {
+		c.String(http.StatusOK, "plain")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ours", nil))
+	want := `{"error":{"code":"CONFLICT","message":"taken"},"requestId":"req-1"}`
+	if w.Code != http.StatusConflict || w.Body.String() != want {
+		t.Fatalf("got %d %q, want %d %q", w.Code, w.Body.String(), http.StatusConflict, want)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "plain" {
+		t.Fatalf("got %d %q, want 200 %q", w.Code, w.Body.String(), "plain")
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// PaginationKey is the context key for parsed pagination parameters
+const PaginationKey = "pagination"
+
+// PaginationDefaults configures how PaginationParams parses list queries
+type PaginationDefaults struct {
+	PerPage      int      // used when perPage is absent or invalid
+	MaxPerPage   int      // perPage is clamped to this value, 0 for no limit
+	Sort         string   // default sort, "-" prefix for descending (e.g. "-created_at")
+	AllowedSorts []string // fields clients may sort by; others fall back to Sort
+}
+
+// PaginationParams returns a middleware that parses page, perPage and sort query
+// parameters once and stores a response.PageRequest in the context
+func PaginationParams(defaults PaginationDefaults) gin.HandlerFunc {
+	if defaults.PerPage <= 0 {
+		defaults.PerPage = 20
+	}
+	allowedSorts := make(map[string]bool, len(defaults.AllowedSorts))
+	for _, field := range defaults.AllowedSorts {
+		allowedSorts[field] = true
+	}
+
+	return func(c *gin.Context) {
+		params := response.PageRequest{Page: 1, PerPage: defaults.PerPage}
+
+		if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+			params.Page = page
+		}
+		if perPage, err := strconv.Atoi(c.Query("perPage")); err == nil && perPage > 0 {
+			params.PerPage = perPage
+		}
+		if defaults.MaxPerPage > 0 && params.PerPage > defaults.MaxPerPage {
+			params.PerPage = defaults.MaxPerPage
+		}
+
+		sort := c.Query("sort")
+		if !allowedSorts[strings.TrimPrefix(sort, "-")] {
+			sort = defaults.Sort
+		}
+		params.Desc = strings.HasPrefix(sort, "-")
+		params.Sort = strings.TrimPrefix(sort, "-")
+
+		c.Set(PaginationKey, params)
+		c.Next()
+	}
+}
+
+// GetPagination gets the pagination parameters parsed by PaginationParams
+func GetPagination(c *gin.Context) (response.PageRequest, bool) {
+	if value, exists := c.Get(PaginationKey); exists {
+		if params, ok := value.(response.PageRequest); ok {
+			return params, true
+		}
+	}
+	return response.PageRequest{}, false
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// parsePagination runs PaginationParams for target and returns what the handler saw
+func parsePagination(t *testing.T, defaults PaginationDefaults, target string) response.PageRequest {
+	t.Helper()
+	var got response.PageRequest
+	var ok bool
+	router := gin.New()
+	router.Use(PaginationParams(defaults))
+	router.GET("/items", func(c *gin.Context) {
+		got, ok = GetPagination(c)
+		c.Status(http.StatusOK)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	if !ok {
+		t.Fatalf("%s: pagination not available to the handler", target)
+	}
+	return got
+}
+
+func TestPaginationParams(t *testing.T) {
+	defaults := PaginationDefaults{
+		PerPage:      20,
+		MaxPerPage:   100,
+		Sort:         "-created_at",
+		AllowedSorts: []string{"name", "created_at"},
+	}
+	tests := []struct {
+		target string
+		want   response.PageRequest
+	}{
+		{"/items", response.PageRequest{Page: 1, PerPage: 20, Sort: "created_at", Desc: true}},
+		{"/items?page=3&perPage=50&sort=name", response.PageRequest{Page: 3, PerPage: 50, Sort: "name"}},
+		{"/items?perPage=1000", response.PageRequest{Page: 1, PerPage: 100, Sort: "created_at", Desc: true}},
+		{"/items?page=0&perPage=-5", response.PageRequest{Page: 1, PerPage: 20, Sort: "created_at", Desc: true}},
+		{"/items?page=abc&perPage=xyz", response.PageRequest{Page: 1, PerPage: 20, Sort: "created_at", Desc: true}},
+		{"/items?sort=-name", response.PageRequest{Page: 1, PerPage: 20, Sort: "name", Desc: true}},
+		{"/items?sort=password", response.PageRequest{Page: 1, PerPage: 20, Sort: "created_at", Desc: true}},
+	}
+	for _, tt := range tests {
+		if got := parsePagination(t, defaults, tt.target); got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestPaginationParamsReadByPaginated(t *testing.T) {
+	router := gin.New()
+	router.Use(PaginationParams(PaginationDefaults{PerPage: 10}))
+	router.GET("/items", func(c *gin.Context) {
+		response.PaginatedFromRequest(c, []int{}, 45)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items?page=2", nil))
+
+	var body response.PaginatedResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	want := response.PaginationMeta{Page: 2, PerPage: 10, Total: 45, TotalPages: 5}
+	if body.Pagination != want {
+		t.Errorf("pagination = %+v, want %+v", body.Pagination, want)
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/services"
+	"github.com/gin-gonic/gin"
+)
+
+// PropagateHeaders returns a middleware that stores the named inbound headers,
+// e.g. config.ServicesConfig.PropagateHeaders, on the request context so a
+// client using services.NewPropagationTransport forwards them downstream.
+// When X-Request-Id is propagated, the request ID assigned by RequestContext
+// or Logger is forwarded in place of the inbound header.
+func PropagateHeaders(names []string) gin.HandlerFunc {
+	var requestID bool
+	for _, name := range names {
+		if http.CanonicalHeaderKey(name) == "X-Request-Id" {
+			requestID = true
+		}
+	}
+
+	return func(c *gin.Context) {
+		header := c.Request.Header
+		if requestID {
+			if id := c.GetString(RequestIDKey); id != "" && header.Get("X-Request-Id") != id {
+				header = header.Clone()
+				header.Set("X-Request-Id", id)
+			}
+		}
+		c.Request = c.Request.WithContext(services.WithPropagatedHeaders(c.Request.Context(), header, names))
+		c.Next()
+	}
+}
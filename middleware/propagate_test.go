@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/services"
+	"github.com/gin-gonic/gin"
+)
+
+// propagatedFor runs a request with inbound headers through PropagateHeaders
+// and returns the headers a propagating client would forward
+func propagatedFor(names []string, inbound http.Header) http.Header {
+	var propagated http.Header
+	router := gin.New()
+	router.Use(RequestContext(), PropagateHeaders(names))
+	router.GET("/", func(c *gin.Context) {
+		propagated = services.PropagatedHeaders(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header = inbound
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	return propagated
+}
+
+func TestPropagateHeadersStoresConfiguredHeaders(t *testing.T) {
+	inbound := http.Header{}
+	inbound.Set("X-B3-Traceid", "463ac35c9f6413ad")
+	inbound.Set("Authorization", "Bearer user-token")
+
+	propagated := propagatedFor([]string{"X-B3-Traceid"}, inbound)
+	if got := propagated.Get("X-B3-Traceid"); got != "463ac35c9f6413ad" {
+		t.Errorf("X-B3-Traceid = %q, want it propagated", got)
+	}
+	if got := propagated.Get("Authorization"); got != "" {
+		t.Errorf("Authorization = %q, want it not propagated", got)
+	}
+}
+
+func TestPropagateHeadersUsesAssignedRequestID(t *testing.T) {
+	propagated := propagatedFor([]string{"x-request-id"}, http.Header{})
+	if propagated.Get("X-Request-Id") == "" {
+		t.Error("assigned request ID not propagated when the inbound header is absent")
+	}
+
+	invalid := http.Header{}
+	invalid.Set("X-Request-Id", "not valid!")
+	if got := propagatedFor([]string{"X-Request-Id"}, invalid).Get("X-Request-Id"); got == "not valid!" {
+		t.Error("rejected inbound request ID propagated instead of the assigned one")
+	}
+
+	if got := propagatedFor(nil, invalid); got != nil {
+		t.Errorf("propagated %v without X-Request-Id configured", got)
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireQueryParams returns a middleware that responds 400 with a validation
+// error listing every missing query parameter, rather than only the first
+func RequireQueryParams(params ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		missing := make(map[string]string)
+		for _, param := range params {
+			if c.Query(param) == "" {
+				missing[param] = "required"
+			}
+		}
+
+		if len(missing) > 0 {
+			response.ValidationError(c, missing)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
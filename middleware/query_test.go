@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireQueryParams(t *testing.T) {
+	router := gin.New()
+	router.Use(RequireQueryParams("workspace", "from", "to"))
+	router.GET("/report", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name        string
+		target      string
+		want        int
+		wantMissing map[string]string
+	}{
+		{"all present", "/report?workspace=w1&from=2024-01-01&to=2024-02-01", http.StatusOK, nil},
+		{"some missing", "/report?workspace=w1", http.StatusBadRequest, map[string]string{"from": "required", "to": "required"}},
+		{"empty value counts as missing", "/report?workspace=&from=a&to=b", http.StatusBadRequest, map[string]string{"workspace": "required"}},
+		{"none present", "/report", http.StatusBadRequest, map[string]string{"workspace": "required", "from": "required", "to": "required"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if tt.wantMissing == nil {
+				return
+			}
+
+			var body struct {
+				Error struct {
+					Code    string            `json:"code"`
+					Details map[string]string `json:"details"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if body.Error.Code != "VALIDATION_ERROR" {
+				t.Errorf("code = %q, want VALIDATION_ERROR", body.Error.Code)
+			}
+			if !reflect.DeepEqual(body.Error.Details, tt.wantMissing) {
+				t.Errorf("details = %v, want %v", body.Error.Details, tt.wantMissing)
+			}
+		})
+	}
+}
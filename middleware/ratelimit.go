@@ -0,0 +1,309 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitStore keeps the token buckets used by RateLimit. A bucket holds up
+// to limit tokens and refills completely over window.
+type RateLimitStore interface {
+	// Allow takes a token from key's bucket, reporting whether one was
+	// available, how many remain, and when the next token is available
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (allowed bool, remaining int, resetAt time.Time, err error)
+}
+
+// RateLimitConfig holds rate limiting configuration
+type RateLimitConfig struct {
+	RequestsPerSecond float64 // sustained rate per key, 0 disables limiting
+	Burst             int     // bucket size, at least 1
+
+	// CleanupInterval is how often the in-memory store drops idle buckets.
+	// Defaults to one minute; ignored when Store is set.
+	CleanupInterval time.Duration
+
+	// KeyFunc identifies the client, c.ClientIP() by default
+	KeyFunc func(*gin.Context) string
+
+	// Store holds the buckets, in memory by default
+	Store RateLimitStore
+
+	// FailClosed rejects requests with 503 when the store fails instead of
+	// letting them through
+	FailClosed bool
+
+	// Requests whose client IP is in ExemptCIDRs (CIDRs or single IPs), whose
+	// path is in ExemptPaths, or whose APIKeyHeader (X-API-Key by default)
+	// holds one of ExemptAPIKeys are never limited. ExemptCIDRs match
+	// c.RemoteIP(), the direct peer, because X-Forwarded-For can be spoofed.
+	ExemptCIDRs   []string
+	ExemptPaths   []string
+	ExemptAPIKeys []string
+	APIKeyHeader  string
+}
+
+// RateLimit returns a middleware that throttles each client with a token
+// bucket, responding 429 with Retry-After once its burst is used up. Every
+// response carries RateLimit-Limit and RateLimit-Remaining headers. Requests
+// are let through if the store fails unless FailClosed is set. It panics on
+// an invalid exempt CIDR.
+func RateLimit(cfg RateLimitConfig) gin.HandlerFunc {
+	if cfg.RequestsPerSecond <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	if cfg.Burst < 1 {
+		cfg.Burst = 1
+	}
+	if cfg.KeyFunc == nil {
+		cfg.KeyFunc = func(c *gin.Context) string { return c.ClientIP() }
+	}
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryRateLimitStore(cfg.CleanupInterval)
+	}
+	exempt := newRateLimitExemptions(cfg)
+
+	// Time for an empty bucket to refill completely
+	window := time.Duration(float64(cfg.Burst) / cfg.RequestsPerSecond * float64(time.Second))
+	limit := strconv.Itoa(cfg.Burst)
+
+	return func(c *gin.Context) {
+		if exempt.matches(c) {
+			c.Next()
+			return
+		}
+
+		allowed, remaining, resetAt, err := cfg.Store.Allow(c.Request.Context(), cfg.KeyFunc(c), cfg.Burst, window)
+		if err != nil {
+			_ = c.Error(err)
+			if cfg.FailClosed {
+				response.ServiceUnavailable(c, "Rate limiter unavailable")
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", limit)
+		c.Header("RateLimit-Remaining", strconv.Itoa(remaining))
+		if !allowed {
+			response.TooManyRequestsWithRetry(c, "Rate limit exceeded", time.Until(resetAt))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimitExemptions holds the parsed exemption lists of a RateLimitConfig
+type rateLimitExemptions struct {
+	networks     []*net.IPNet
+	paths        map[string]bool
+	apiKeys      map[string]bool
+	apiKeyHeader string
+}
+
+func newRateLimitExemptions(cfg RateLimitConfig) *rateLimitExemptions {
+	exempt := &rateLimitExemptions{
+		paths:        make(map[string]bool, len(cfg.ExemptPaths)),
+		apiKeys:      make(map[string]bool, len(cfg.ExemptAPIKeys)),
+		apiKeyHeader: cfg.APIKeyHeader,
+	}
+	if exempt.apiKeyHeader == "" {
+		exempt.apiKeyHeader = "X-API-Key"
+	}
+
+	for _, cidr := range cfg.ExemptCIDRs {
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic("middleware: invalid rate limit exempt CIDR " + cidr)
+		}
+		exempt.networks = append(exempt.networks, network)
+	}
+	for _, path := range cfg.ExemptPaths {
+		exempt.paths[path] = true
+	}
+	for _, key := range cfg.ExemptAPIKeys {
+		if key != "" {
+			exempt.apiKeys[key] = true
+		}
+	}
+	return exempt
+}
+
+// matches reports whether the request is exempt from rate limiting
+func (e *rateLimitExemptions) matches(c *gin.Context) bool {
+	if e.paths[c.Request.URL.Path] {
+		return true
+	}
+	if len(e.apiKeys) > 0 {
+		if key := c.GetHeader(e.apiKeyHeader); key != "" && e.apiKeys[key] {
+			return true
+		}
+	}
+	if len(e.networks) > 0 {
+		// Not c.ClientIP(), which a client can set through X-Forwarded-For
+		if ip := net.ParseIP(c.RemoteIP()); ip != nil {
+			for _, network := range e.networks {
+				if network.Contains(ip) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// tokenBucket is the state of one key in the memory store
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// MemoryRateLimitStore is an in-process RateLimitStore. Each replica counts
+// separately, so use a shared store when running several.
+type MemoryRateLimitStore struct {
+	mu              sync.Mutex
+	buckets         map[string]*tokenBucket
+	cleanupInterval time.Duration
+	lastCleanup     time.Time
+}
+
+// NewMemoryRateLimitStore creates an in-memory store that drops buckets idle
+// long enough to have refilled, checking every cleanupInterval (default one minute)
+func NewMemoryRateLimitStore(cleanupInterval time.Duration) *MemoryRateLimitStore {
+	if cleanupInterval <= 0 {
+		cleanupInterval = time.Minute
+	}
+	return &MemoryRateLimitStore{
+		buckets:         make(map[string]*tokenBucket),
+		cleanupInterval: cleanupInterval,
+		lastCleanup:     time.Now(),
+	}
+}
+
+// Allow takes a token from key's bucket
+func (s *MemoryRateLimitStore) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	// Tokens per nanosecond
+	rate := float64(limit) / float64(window)
+
+	if now.Sub(s.lastCleanup) >= s.cleanupInterval {
+		for k, bucket := range s.buckets {
+			if now.Sub(bucket.updated) >= window {
+				delete(s.buckets, k)
+			}
+		}
+		s.lastCleanup = now
+	}
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limit), updated: now}
+		s.buckets[key] = bucket
+	}
+	bucket.tokens = math.Min(float64(limit), bucket.tokens+float64(now.Sub(bucket.updated))*rate)
+	bucket.updated = now
+
+	if bucket.tokens < 1 {
+		wait := time.Duration((1 - bucket.tokens) / rate)
+		return false, 0, now.Add(wait), nil
+	}
+	bucket.tokens--
+	return true, int(bucket.tokens), now, nil
+}
+
+// tokenBucketScript refills and takes a token from the bucket in KEYS[1]
+// atomically, using the Redis clock so all replicas agree on the time.
+// ARGV[1] is the bucket size and ARGV[2] the refill window in milliseconds.
+// Returns {allowed, remaining, wait in milliseconds}.
+const tokenBucketScript = `
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local rate = limit / window
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or limit
+local ts = tonumber(state[2]) or now
+tokens = math.min(limit, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], window)
+return {allowed, math.floor(tokens), wait}
+`
+
+// RedisRateLimitStore is a RateLimitStore shared across replicas through Redis
+type RedisRateLimitStore struct {
+	eval func(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+// NewRedisRateLimitStore creates a rate limit store backed by a Lua token
+// bucket script. eval should run script with keys and args and return its
+// result, e.g. with go-redis:
+//
+//	func(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+//		return client.Eval(ctx, script, keys, args...).Result()
+//	}
+func NewRedisRateLimitStore(eval func(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)) *RedisRateLimitStore {
+	return &RedisRateLimitStore{eval: eval}
+}
+
+// Allow takes a token from key's bucket
+func (s *RedisRateLimitStore) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, int, time.Time, error) {
+	windowMs := window.Milliseconds()
+	if windowMs < 1 {
+		windowMs = 1
+	}
+
+	result, err := s.eval(ctx, tokenBucketScript, []string{"ratelimit:" + key}, limit, windowMs)
+	if err != nil {
+		return false, 0, time.Time{}, fmt.Errorf("rate limit script failed: %w", err)
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 3 {
+		return false, 0, time.Time{}, fmt.Errorf("unexpected rate limit script result %v", result)
+	}
+	var parsed [3]int64
+	for i, value := range values {
+		n, ok := value.(int64)
+		if !ok {
+			return false, 0, time.Time{}, fmt.Errorf("unexpected rate limit script result %v", result)
+		}
+		parsed[i] = n
+	}
+
+	allowed, remaining, wait := parsed[0] == 1, int(parsed[1]), time.Duration(parsed[2])*time.Millisecond
+	return allowed, remaining, time.Now().Add(wait), nil
+}
@@ -0,0 +1,249 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitedRouter returns a router limiting /ping and /health with cfg
+func rateLimitedRouter(cfg RateLimitConfig) *gin.Engine {
+	router := gin.New()
+	router.Use(RateLimit(cfg))
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+// sendFrom sends a GET for path from remoteAddr, applying headers as
+// alternating name and value pairs
+func sendFrom(router *gin.Engine, path, remoteAddr string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitBurstThenLimited(t *testing.T) {
+	router := rateLimitedRouter(RateLimitConfig{RequestsPerSecond: 0.5, Burst: 2})
+
+	for i, remaining := range []string{"1", "0"} {
+		w := sendFrom(router, "/ping", "192.0.2.1:1234")
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i, w.Code)
+		}
+		if got := w.Header().Get("RateLimit-Limit"); got != "2" {
+			t.Errorf("request %d: RateLimit-Limit = %q, want 2", i, got)
+		}
+		if got := w.Header().Get("RateLimit-Remaining"); got != remaining {
+			t.Errorf("request %d: RateLimit-Remaining = %q, want %s", i, got, remaining)
+		}
+	}
+
+	w := sendFrom(router, "/ping", "192.0.2.1:1234")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request beyond the burst: status = %d, want 429", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2 at half a token per second", got)
+	}
+}
+
+func TestRateLimitZeroRateIsNoOp(t *testing.T) {
+	router := rateLimitedRouter(RateLimitConfig{Burst: 1})
+
+	for i := 0; i < 5; i++ {
+		if w := sendFrom(router, "/ping", "192.0.2.1:1234"); w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "" {
+			t.Fatalf("request %d: status = %d, RateLimit-Limit = %q, want an unlimited 200", i, w.Code, w.Header().Get("RateLimit-Limit"))
+		}
+	}
+}
+
+func TestRateLimitKeyFunc(t *testing.T) {
+	router := rateLimitedRouter(RateLimitConfig{
+		RequestsPerSecond: 0.1,
+		Burst:             1,
+		KeyFunc:           func(c *gin.Context) string { return c.GetHeader("X-Tenant") },
+	})
+
+	if w := sendFrom(router, "/ping", "192.0.2.1:1234", "X-Tenant", "a"); w.Code != http.StatusOK {
+		t.Fatalf("first request for tenant a: status = %d, want 200", w.Code)
+	}
+	if w := sendFrom(router, "/ping", "192.0.2.2:1234", "X-Tenant", "a"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("tenant a from another IP: status = %d, want 429 from the shared bucket", w.Code)
+	}
+	if w := sendFrom(router, "/ping", "192.0.2.1:1234", "X-Tenant", "b"); w.Code != http.StatusOK {
+		t.Errorf("tenant b: status = %d, want 200 from its own bucket", w.Code)
+	}
+}
+
+func TestRateLimitExemptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		remoteAddr string
+		headers    []string
+		exempt     bool
+	}{
+		{"exempt path", "/health", "192.0.2.1:1234", nil, true},
+		{"exempt CIDR", "/ping", "10.1.2.3:1234", nil, true},
+		{"exempt single IP", "/ping", "198.51.100.7:1234", nil, true},
+		{"exempt API key in custom header", "/ping", "192.0.2.1:1234", []string{"X-Service-Key", "internal-key"}, true},
+		{"exempt API key in default header", "/ping", "192.0.2.1:1234", []string{"X-API-Key", "internal-key"}, false},
+		{"unknown API key", "/ping", "192.0.2.1:1234", []string{"X-Service-Key", "other-key"}, false},
+		{"other IP", "/ping", "192.0.2.1:1234", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := rateLimitedRouter(RateLimitConfig{
+				RequestsPerSecond: 0.1,
+				Burst:             1,
+				ExemptCIDRs:       []string{"10.0.0.0/8", "198.51.100.7"},
+				ExemptPaths:       []string{"/health"},
+				ExemptAPIKeys:     []string{"internal-key"},
+				APIKeyHeader:      "X-Service-Key",
+			})
+
+			limited := 0
+			for i := 0; i < 3; i++ {
+				if sendFrom(router, tt.path, tt.remoteAddr, tt.headers...).Code == http.StatusTooManyRequests {
+					limited++
+				}
+			}
+			if exempt := limited == 0; exempt != tt.exempt {
+				t.Errorf("%d of 3 requests limited, want exempt = %v", limited, tt.exempt)
+			}
+		})
+	}
+}
+
+func TestRateLimitExemptCIDRIgnoresForwardedFor(t *testing.T) {
+	router := rateLimitedRouter(RateLimitConfig{
+		RequestsPerSecond: 0.1,
+		Burst:             1,
+		ExemptCIDRs:       []string{"10.0.0.0/8"},
+	})
+
+	limited := 0
+	for i := 0; i < 3; i++ {
+		if sendFrom(router, "/ping", "192.0.2.1:1234", "X-Forwarded-For", "10.1.2.3").Code == http.StatusTooManyRequests {
+			limited++
+		}
+	}
+	if limited != 2 {
+		t.Errorf("%d of 3 requests with a spoofed X-Forwarded-For limited, want 2", limited)
+	}
+}
+
+func TestRateLimitInvalidExemptCIDRPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for an invalid exempt CIDR")
+		}
+	}()
+	RateLimit(RateLimitConfig{RequestsPerSecond: 1, ExemptCIDRs: []string{"not-an-ip"}})
+}
+
+// failingStore is a RateLimitStore whose every call fails
+type failingStore struct{}
+
+func (failingStore) Allow(context.Context, string, int, time.Duration) (bool, int, time.Time, error) {
+	return false, 0, time.Time{}, errors.New("store down")
+}
+
+func TestRateLimitStoreFailure(t *testing.T) {
+	for _, failClosed := range []bool{false, true} {
+		router := rateLimitedRouter(RateLimitConfig{RequestsPerSecond: 1, Store: failingStore{}, FailClosed: failClosed})
+
+		want := http.StatusOK
+		if failClosed {
+			want = http.StatusServiceUnavailable
+		}
+		if w := sendFrom(router, "/ping", "192.0.2.1:1234"); w.Code != want {
+			t.Errorf("FailClosed=%v: status = %d, want %d", failClosed, w.Code, want)
+		}
+	}
+}
+
+func TestMemoryRateLimitStoreRefillsAndCleansUp(t *testing.T) {
+	store := NewMemoryRateLimitStore(time.Millisecond)
+	ctx := context.Background()
+	window := 20 * time.Millisecond
+
+	if allowed, _, _, _ := store.Allow(ctx, "a", 1, window); !allowed {
+		t.Fatal("first request denied")
+	}
+	allowed, _, resetAt, _ := store.Allow(ctx, "a", 1, window)
+	if allowed {
+		t.Fatal("second request allowed with an empty bucket")
+	}
+	if wait := time.Until(resetAt); wait <= 0 || wait > window {
+		t.Errorf("reset in %s, want within the %s window", wait, window)
+	}
+
+	time.Sleep(2 * window)
+	if allowed, _, _, _ := store.Allow(ctx, "b", 1, window); !allowed {
+		t.Fatal("request for a new key denied")
+	}
+	store.mu.Lock()
+	_, kept := store.buckets["a"]
+	store.mu.Unlock()
+	if kept {
+		t.Error("idle bucket kept after it refilled")
+	}
+	if allowed, _, _, _ := store.Allow(ctx, "a", 1, window); !allowed {
+		t.Error("request denied after the bucket refilled")
+	}
+}
+
+func TestRedisRateLimitStore(t *testing.T) {
+	var gotKeys []string
+	var gotArgs []interface{}
+	var result interface{} = []interface{}{int64(0), int64(0), int64(1500)}
+	store := NewRedisRateLimitStore(func(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+		gotKeys, gotArgs = keys, args
+		return result, nil
+	})
+
+	allowed, remaining, resetAt, err := store.Allow(context.Background(), "192.0.2.1", 5, 10*time.Second)
+	if err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	if allowed || remaining != 0 {
+		t.Errorf("Allow() = %v, %d, want denied with 0 remaining", allowed, remaining)
+	}
+	if wait := time.Until(resetAt); wait < time.Second || wait > 1500*time.Millisecond {
+		t.Errorf("reset in %s, want about 1.5s", wait)
+	}
+	if len(gotKeys) != 1 || gotKeys[0] != "ratelimit:192.0.2.1" {
+		t.Errorf("keys = %v, want [ratelimit:192.0.2.1]", gotKeys)
+	}
+	if len(gotArgs) != 2 || gotArgs[0] != 5 || gotArgs[1] != int64(10000) {
+		t.Errorf("args = %v, want [5 10000]", gotArgs)
+	}
+
+	for _, bad := range []interface{}{"OK", []interface{}{int64(1)}, []interface{}{int64(1), "2", int64(0)}} {
+		result = bad
+		if _, _, _, err := store.Allow(context.Background(), "k", 5, time.Second); err == nil {
+			t.Errorf("result %v: no error", bad)
+		}
+	}
+}
+
+func TestRedisRateLimitStoreEvalError(t *testing.T) {
+	store := NewRedisRateLimitStore(func(context.Context, string, []string, ...interface{}) (interface{}, error) {
+		return nil, errors.New("connection refused")
+	})
+	if _, _, _, err := store.Allow(context.Background(), "k", 5, time.Second); err == nil {
+		t.Fatal("Allow() succeeded with a failing eval")
+	}
+}
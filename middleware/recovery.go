@@ -1,38 +1,54 @@
 package middleware
 
 import (
-	"net/http"
 	"runtime/debug"
 
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
+// RecoveryConfig holds optional panic logging features
+type RecoveryConfig struct {
+	// LogRequestBody logs the masked request body captured by CaptureRequestBody
+	// so on-call can reproduce the panic
+	LogRequestBody bool
+}
+
 // Recovery returns a middleware that recovers from panics
 func Recovery(logger *zap.Logger) gin.HandlerFunc {
+	return RecoveryWithConfig(logger, RecoveryConfig{})
+}
+
+// RecoveryWithConfig returns a recovery middleware with optional features enabled
+func RecoveryWithConfig(logger *zap.Logger, config RecoveryConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// Let SafeGo log goroutine panics to the same logger
+		c.Set(recoveryLoggerKey, logger)
+
 		defer func() {
 			if err := recover(); err != nil {
-				// Get request ID if available
-				requestID := GetRequestID(c)
+				// Get request ID, storing a new one so the response reports the same ID
+				requestID := ensureRequestID(c)
 
 				// Log the panic
-				logger.Error("Panic recovered",
+				fields := []zap.Field{
 					zap.String("request_id", requestID),
 					zap.Any("error", err),
 					zap.String("stack", string(debug.Stack())),
 					zap.String("path", c.Request.URL.Path),
 					zap.String("method", c.Request.Method),
-				)
+				}
+				if config.LogRequestBody {
+					if body, ok := capturedBody(c); ok {
+						fields = append(fields, zap.String("request_body", maskBody(body, c.ContentType())))
+					}
+				}
+				logger.Error("Panic recovered", fields...)
 
 				// Return 500 error
-				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-					"error": map[string]interface{}{
-						"code":    "INTERNAL_ERROR",
-						"message": "Internal server error",
-					},
-					"requestId": requestID,
-				})
+				response.InternalError(c, "Internal server error")
+				c.Abort()
 			}
 		}()
 
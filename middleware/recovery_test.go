@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// panicRouter returns a router that reads the body into *seen and then panics
+func panicRouter(config RecoveryConfig, maxBytes int, seen *string) (*gin.Engine, func() []map[string]interface{}) {
+	logger, logs := observedLogger()
+	router := gin.New()
+	router.Use(RecoveryWithConfig(logger, config))
+	router.Use(CaptureRequestBody(maxBytes))
+	router.POST("/login", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		*seen = string(body)
+		panic("boom")
+	})
+
+	entries := func() []map[string]interface{} {
+		var fields []map[string]interface{}
+		for _, entry := range logs.FilterMessage("Panic recovered").All() {
+			fields = append(fields, entry.ContextMap())
+		}
+		return fields
+	}
+	return router, entries
+}
+
+func TestRecoveryLogsMaskedRequestBody(t *testing.T) {
+	var seen string
+	router, entries := panicRouter(RecoveryConfig{LogRequestBody: true}, 1024, &seen)
+
+	body := `{"email":"alice@example.com","password":"hunter2","profile":{"api_key":"k-1"}}`
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	if seen != body {
+		t.Errorf("handler read %q, want the full body", seen)
+	}
+
+	logged := entries()
+	if len(logged) != 1 {
+		t.Fatalf("got %d panic logs, want 1", len(logged))
+	}
+	requestBody, _ := logged[0]["request_body"].(string)
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(requestBody), &parsed); err != nil {
+		t.Fatalf("logged body %q is not JSON: %v", requestBody, err)
+	}
+	if parsed["email"] != "alice@example.com" {
+		t.Errorf("email = %v, want it logged", parsed["email"])
+	}
+	if parsed["password"] != maskedValue {
+		t.Errorf("password = %v, want masked", parsed["password"])
+	}
+	if profile, _ := parsed["profile"].(map[string]interface{}); profile["api_key"] != maskedValue {
+		t.Errorf("nested api_key = %v, want masked", profile["api_key"])
+	}
+	if strings.Contains(requestBody, "hunter2") {
+		t.Error("secret leaked into the panic log")
+	}
+}
+
+func TestRecoveryBodyCaptureIsBounded(t *testing.T) {
+	var seen string
+	router, entries := panicRouter(RecoveryConfig{LogRequestBody: true}, 16, &seen)
+
+	body := `{"password":"hunter2","padding":"` + strings.Repeat("x", 100) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if seen != body {
+		t.Errorf("handler read %d bytes, want all %d", len(seen), len(body))
+	}
+	requestBody, _ := entries()[0]["request_body"].(string)
+	if strings.Contains(requestBody, "hunter2") || !strings.Contains(requestBody, "16 bytes") {
+		t.Errorf("truncated body logged as %q, want a summary", requestBody)
+	}
+}
+
+func TestRecoveryOmitsBodyByDefault(t *testing.T) {
+	var seen string
+	router, entries := panicRouter(RecoveryConfig{}, 1024, &seen)
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"a":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if _, ok := entries()[0]["request_body"]; ok {
+		t.Error("request body logged without LogRequestBody")
+	}
+}
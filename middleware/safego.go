@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// recoveryLoggerKey is the context key for the logger Recovery was created with
+const recoveryLoggerKey = "recovery_logger"
+
+// SafeGo runs fn in a new goroutine that recovers from panics, logging them
+// with the request ID instead of crashing the process. Recovery only covers the
+// request goroutine, so use this for background work spawned by handlers.
+// Panics are logged to the Recovery middleware's logger, or zap.L() without it.
+// fn must not use c, which is recycled once the request completes.
+func SafeGo(c *gin.Context, fn func()) {
+	requestID := GetRequestID(c)
+	logger := zap.L()
+	if l, ok := c.Get(recoveryLoggerKey); ok {
+		if zl, ok := l.(*zap.Logger); ok {
+			logger = zl
+		}
+	}
+	path := c.Request.URL.Path
+
+	go func() {
+		defer func() {
+			if err := recover(); err != nil {
+				logger.Error("Panic recovered in goroutine",
+					zap.String("request_id", requestID),
+					zap.Any("error", err),
+					zap.String("stack", string(debug.Stack())),
+					zap.String("path", path),
+				)
+			}
+		}()
+
+		fn()
+	}()
+}
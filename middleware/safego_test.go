@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// waitForLog waits until count reports at least one entry
+func waitForLog(t *testing.T, count func() int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for count() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the panic to be logged")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSafeGoRecoversAndLogsPanic(t *testing.T) {
+	logger, logs := observedLogger()
+	router := gin.New()
+	router.Use(RequestContext(), Recovery(logger))
+	router.GET("/work", func(c *gin.Context) {
+		SafeGo(c, func() { panic("background failure") })
+		c.Status(http.StatusAccepted)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/work", nil)
+	req.Header.Set("X-Request-ID", "req-bg")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202", w.Code)
+	}
+
+	waitForLog(t, func() int { return logs.FilterMessage("Panic recovered in goroutine").Len() })
+	fields := logs.FilterMessage("Panic recovered in goroutine").All()[0].ContextMap()
+	if fields["request_id"] != "req-bg" || fields["error"] != "background failure" || fields["path"] != "/work" {
+		t.Errorf("fields = %v, want the request ID, panic value and path", fields)
+	}
+	if fields["stack"] == "" {
+		t.Error("stack not logged")
+	}
+}
+
+func TestSafeGoFallsBackToGlobalLogger(t *testing.T) {
+	logger, logs := observedLogger()
+	defer zap.ReplaceGlobals(logger)()
+
+	router := gin.New()
+	router.GET("/work", func(c *gin.Context) {
+		SafeGo(c, func() { panic("no recovery middleware") })
+		c.Status(http.StatusAccepted)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/work", nil))
+
+	waitForLog(t, func() int { return logs.FilterMessage("Panic recovered in goroutine").Len() })
+}
+
+func TestSafeGoRunsFunction(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	done := make(chan struct{})
+	SafeGo(c, func() { close(done) })
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("fn did not run")
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"go.uber.org/zap"
+)
+
+// ResponseSchema returns a middleware that validates JSON responses against the
+// schema registered for the route (keyed by route pattern, e.g. "/users/:id")
+// to catch API contract drift during development. Mismatches are logged, or
+// replaced with a 500 response when strict is true.
+// Outside gin debug mode it is a no-op.
+func ResponseSchema(logger *zap.Logger, schemas map[string]*jsonschema.Schema, strict bool) gin.HandlerFunc {
+	if !gin.IsDebugging() {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		schema, ok := schemas[c.FullPath()]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		buffered := newBufferedWriter(original)
+		c.Writer = buffered
+		defer func() {
+			c.Writer = original
+		}()
+
+		c.Next()
+
+		c.Writer = original
+		body := buffered.body.Bytes()
+		if len(body) == 0 || !strings.Contains(original.Header().Get("Content-Type"), "json") {
+			buffered.flush()
+			return
+		}
+
+		if err := validateJSON(schema, body); err != nil {
+			logger.Warn("Response does not match schema",
+				zap.String("request_id", GetRequestID(c)),
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.FullPath()),
+				zap.Int("status", buffered.status),
+				zap.Error(err),
+			)
+			if strict {
+				original.Header().Del("Content-Length")
+				response.InternalError(c, "Response does not match schema")
+				return
+			}
+		}
+		buffered.flush()
+	}
+}
+
+// validateJSON decodes body and validates it against schema
+func validateJSON(schema *jsonschema.Schema, body []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	return schema.Validate(value)
+}
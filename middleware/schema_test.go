@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"go.uber.org/zap"
+)
+
+const userSchema = `{
+	"type": "object",
+	"required": ["id", "name"],
+	"properties": {"id": {"type": "integer"}, "name": {"type": "string"}}
+}`
+
+// schemaRouter returns a router validating GET /users/:id against userSchema.
+// /users/1 conforms and /users/2 does not.
+func schemaRouter(t *testing.T, logger *zap.Logger, strict bool) *gin.Engine {
+	t.Helper()
+	schema, err := jsonschema.CompileString("user.json", userSchema)
+	if err != nil {
+		t.Fatalf("compile schema: %v", err)
+	}
+
+	router := gin.New()
+	router.Use(ResponseSchema(logger, map[string]*jsonschema.Schema{"/users/:id": schema}, strict))
+	router.GET("/users/:id", func(c *gin.Context) {
+		if c.Param("id") == "1" {
+			c.JSON(http.StatusOK, gin.H{"id": 1, "name": "alice"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"id": "two"})
+	})
+	return router
+}
+
+// inDebugMode switches gin to debug mode until the test ends
+func inDebugMode(t *testing.T) {
+	gin.SetMode(gin.DebugMode)
+	t.Cleanup(func() { gin.SetMode(gin.ReleaseMode) })
+}
+
+func TestResponseSchemaConformingResponse(t *testing.T) {
+	inDebugMode(t)
+	logger, logs := observedLogger()
+	router := schemaRouter(t, logger, true)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	if w.Code != http.StatusOK || w.Body.String() != `{"id":1,"name":"alice"}` {
+		t.Errorf("got %d %q, want the handler's response", w.Code, w.Body.String())
+	}
+	if logs.Len() != 0 {
+		t.Errorf("logged %d mismatches for a conforming response", logs.Len())
+	}
+}
+
+func TestResponseSchemaMismatchLogged(t *testing.T) {
+	inDebugMode(t)
+	logger, logs := observedLogger()
+	router := schemaRouter(t, logger, false)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/2", nil))
+
+	if w.Code != http.StatusOK || w.Body.String() != `{"id":"two"}` {
+		t.Errorf("got %d %q, want the handler's response in non-strict mode", w.Code, w.Body.String())
+	}
+	if entries := logs.FilterMessage("Response does not match schema").All(); len(entries) != 1 {
+		t.Errorf("got %d mismatch logs, want 1", len(entries))
+	}
+}
+
+func TestResponseSchemaMismatchStrict(t *testing.T) {
+	inDebugMode(t)
+	router := schemaRouter(t, zap.NewNop(), true)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/2", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500 in strict mode", w.Code)
+	}
+}
+
+func TestResponseSchemaNoOpInRelease(t *testing.T) {
+	logger, logs := observedLogger()
+	router := schemaRouter(t, logger, true)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/2", nil))
+
+	if w.Code != http.StatusOK || logs.Len() != 0 {
+		t.Errorf("got %d with %d logs, want the response untouched in release mode", w.Code, logs.Len())
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"strconv"
+	"sync/atomic"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/health"
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// shedRetryAfter is the Retry-After hint, in seconds, sent with shed requests
+const shedRetryAfter = 5
+
+// probePaths are always admitted so orchestration and scraping keep working
+var probePaths = []string{"/health", "/healthz", "/ready", "/readyz", "/started", "/metrics"}
+
+// ShedWhenNotReady returns a middleware that responds 503 with Retry-After
+// while h reports failing dependencies, instead of accepting requests that
+// would fail on them. It uses the last readiness result rather than running
+// checks, and keeps serving while draining so in-flight traffic is not cut off
+// before shutdown. Probe and metrics paths, and skipPaths, pass through.
+func ShedWhenNotReady(h *health.Handler, skipPaths ...string) gin.HandlerFunc {
+	skipMap := make(map[string]bool)
+	for _, path := range append(probePaths, skipPaths...) {
+		skipMap[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if skipMap[c.Request.URL.Path] || h.Healthy() {
+			c.Next()
+			return
+		}
+
+		c.Header("Retry-After", strconv.Itoa(shedRetryAfter))
+		response.ServiceUnavailable(c, "Service is not ready")
+		c.Abort()
+	}
+}
+
+// GlobalLimit returns a middleware that caps in-flight requests across all
+// clients, responding 503 with Retry-After once max requests are in progress
+// and counting them in app_shed_requests_total. Probe and metrics paths pass
+// through without counting towards the cap.
+func GlobalLimit(max int) gin.HandlerFunc {
+	shedTotal := registerCollector(prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "app_shed_requests_total",
+		Help: "Total number of requests rejected by the global in-flight limit",
+	}))
+	skipMap := make(map[string]bool, len(probePaths))
+	for _, path := range probePaths {
+		skipMap[path] = true
+	}
+
+	var inFlight atomic.Int64
+	return func(c *gin.Context) {
+		if skipMap[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		if inFlight.Add(1) > int64(max) {
+			inFlight.Add(-1)
+			shedTotal.Inc()
+			c.Header("Retry-After", strconv.Itoa(shedRetryAfter))
+			response.ServiceUnavailable(c, "Server is overloaded")
+			c.Abort()
+			return
+		}
+		// Release on completion and on panic
+		defer inFlight.Add(-1)
+
+		c.Next()
+	}
+}
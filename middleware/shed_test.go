@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/health"
+	"github.com/gin-gonic/gin"
+)
+
+// toggleChecker reports unhealthy while down is set
+type toggleChecker struct {
+	down atomic.Bool
+}
+
+func (t *toggleChecker) Name() string { return "database" }
+
+func (t *toggleChecker) Check(ctx context.Context) health.ComponentCheck {
+	if t.down.Load() {
+		return health.ComponentCheck{Status: health.StatusUnhealthy, Message: "connection refused"}
+	}
+	return health.ComponentCheck{Status: health.StatusHealthy}
+}
+
+// shedRouter returns a router shedding /work while h is not ready
+func shedRouter(h *health.Handler) *gin.Engine {
+	router := gin.New()
+	router.Use(ShedWhenNotReady(h, "/skip"))
+	h.RegisterRoutes(router)
+	for _, path := range []string{"/work", "/skip", "/metrics"} {
+		router.GET(path, func(c *gin.Context) { c.Status(http.StatusOK) })
+	}
+	return router
+}
+
+// getPath sends GET path to router
+func getPath(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestShedWhenNotReady(t *testing.T) {
+	checker := &toggleChecker{}
+	h := health.NewHandler()
+	h.AddChecker(checker)
+	router := shedRouter(h)
+
+	if w := getPath(router, "/work"); w.Code != http.StatusOK {
+		t.Fatalf("before any probe: status = %d, want 200", w.Code)
+	}
+
+	checker.down.Store(true)
+	if w := getPath(router, "/ready"); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("readiness with a failing checker: status = %d, want 503", w.Code)
+	}
+	w := getPath(router, "/work")
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") != "5" {
+		t.Errorf("not ready: got %d Retry-After %q, want 503 with Retry-After 5", w.Code, w.Header().Get("Retry-After"))
+	}
+	for _, path := range []string{"/health", "/metrics", "/skip"} {
+		if w := getPath(router, path); w.Code != http.StatusOK {
+			t.Errorf("not ready: %s status = %d, want 200", path, w.Code)
+		}
+	}
+
+	checker.down.Store(false)
+	getPath(router, "/ready")
+	if w := getPath(router, "/work"); w.Code != http.StatusOK {
+		t.Errorf("recovered: status = %d, want 200", w.Code)
+	}
+}
+
+func TestShedWhenNotReadyServesWhileDraining(t *testing.T) {
+	h := health.NewHandler()
+	h.AddChecker(&toggleChecker{})
+	router := shedRouter(h)
+	getPath(router, "/ready")
+
+	h.Drain()
+	if w := getPath(router, "/ready"); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("draining readiness: status = %d, want 503", w.Code)
+	}
+	if w := getPath(router, "/work"); w.Code != http.StatusOK {
+		t.Errorf("draining: status = %d, want 200 while in-flight traffic finishes", w.Code)
+	}
+}
+
+// shedCount returns the current app_shed_requests_total value
+func shedCount(t *testing.T) float64 {
+	t.Helper()
+	family := gatherFamily(t, "app_shed_requests_total")
+	if family == nil || len(family.GetMetric()) != 1 {
+		t.Fatal("app_shed_requests_total not registered")
+	}
+	return family.GetMetric()[0].GetCounter().GetValue()
+}
+
+// globalLimitRouter returns a router capped at max in-flight requests whose
+// /block waits for release after signalling entered, and whose /panic panics
+func globalLimitRouter(max int, entered chan<- struct{}, release <-chan struct{}) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.CustomRecovery(func(c *gin.Context, _ any) { c.AbortWithStatus(http.StatusInternalServerError) }))
+	router.Use(GlobalLimit(max))
+	router.GET("/block", func(c *gin.Context) {
+		entered <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+	router.GET("/panic", func(c *gin.Context) { panic("boom") })
+	for _, path := range []string{"/work", "/health"} {
+		router.GET(path, func(c *gin.Context) { c.Status(http.StatusOK) })
+	}
+	return router
+}
+
+func TestGlobalLimitShedsBeyondCap(t *testing.T) {
+	entered, release := make(chan struct{}), make(chan struct{})
+	router := globalLimitRouter(2, entered, release)
+	before := shedCount(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			getPath(router, "/block")
+		}()
+		<-entered
+	}
+
+	for i := 0; i < 3; i++ {
+		w := getPath(router, "/work")
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("request %d over the cap: status = %d, want 503", i, w.Code)
+		}
+		if w.Header().Get("Retry-After") == "" {
+			t.Error("shed response has no Retry-After")
+		}
+	}
+	if w := getPath(router, "/health"); w.Code != http.StatusOK {
+		t.Errorf("probe at the cap: status = %d, want 200", w.Code)
+	}
+	if got := shedCount(t) - before; got != 3 {
+		t.Errorf("app_shed_requests_total grew by %v, want 3", got)
+	}
+
+	close(release)
+	wg.Wait()
+	if w := getPath(router, "/work"); w.Code != http.StatusOK {
+		t.Errorf("request after completion: status = %d, want 200", w.Code)
+	}
+}
+
+func TestGlobalLimitReleasesOnPanic(t *testing.T) {
+	router := globalLimitRouter(1, nil, nil)
+
+	for i := 0; i < 3; i++ {
+		if w := getPath(router, "/panic"); w.Code != http.StatusInternalServerError {
+			t.Fatalf("panic %d: status = %d, want 500 rather than shed", i, w.Code)
+		}
+	}
+	if w := getPath(router, "/work"); w.Code != http.StatusOK {
+		t.Errorf("request after panics: status = %d, want 200", w.Code)
+	}
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultSignatureMaxBytes bounds the body VerifySignature buffers before the
+// sender is authenticated
+const defaultSignatureMaxBytes = 1 << 20
+
+// VerifySignature returns a middleware that verifies an HMAC signature of the raw
+// request body sent in header, as used by inbound webhooks. algo is "sha1",
+// "sha256" or "sha512" in any case; the header holds the hex digest, optionally
+// prefixed with "<algo>=" (e.g. "sha256=ab12..."). The body is restored for the
+// handler. Bodies over 1 MiB are rejected with 413; use VerifySignatureWithLimit
+// for another limit. It panics on an unsupported algo.
+func VerifySignature(secret string, header string, algo string) gin.HandlerFunc {
+	return VerifySignatureWithLimit(secret, header, algo, defaultSignatureMaxBytes)
+}
+
+// VerifySignatureWithLimit is VerifySignature with bodies over maxBytes
+// rejected with 413. A maxBytes of 0 or less uses the 1 MiB default.
+func VerifySignatureWithLimit(secret string, header string, algo string, maxBytes int64) gin.HandlerFunc {
+	algo = strings.ToLower(algo)
+	newHash := hashFunc(algo)
+	if newHash == nil {
+		panic("middleware: unsupported signature algorithm " + algo)
+	}
+	if maxBytes <= 0 {
+		maxBytes = defaultSignatureMaxBytes
+	}
+	prefix := algo + "="
+
+	return func(c *gin.Context) {
+		signature := c.GetHeader(header)
+		if len(signature) >= len(prefix) && strings.EqualFold(signature[:len(prefix)], prefix) {
+			signature = signature[len(prefix):]
+		}
+		if signature == "" {
+			response.Unauthorized(c, "Missing signature")
+			c.Abort()
+			return
+		}
+		expected, err := hex.DecodeString(signature)
+		if err != nil {
+			response.Unauthorized(c, "Invalid signature")
+			c.Abort()
+			return
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			body, err = io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes))
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				response.ErrorForStatus(c, http.StatusRequestEntityTooLarge, "Request body too large")
+				c.Abort()
+				return
+			}
+			if err != nil {
+				response.BadRequest(c, "Failed to read request body")
+				c.Abort()
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		mac := hmac.New(newHash, []byte(secret))
+		mac.Write(body)
+		if !hmac.Equal(mac.Sum(nil), expected) {
+			response.Unauthorized(c, "Invalid signature")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// hashFunc returns the hash constructor for an algorithm name
+func hashFunc(algo string) func() hash.Hash {
+	switch algo {
+	case "sha1":
+		return sha1.New
+	case "sha256":
+		return sha256.New
+	case "sha512":
+		return sha512.New
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sign returns the hex HMAC of body with secret
+func sign(newHash func() hash.Hash, secret, body string) string {
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookRouter returns a router verifying /hook, which echoes the body it reads
+func webhookRouter(header, algo string) *gin.Engine {
+	router := gin.New()
+	router.Use(VerifySignature("webhook-secret", header, algo))
+	router.POST("/hook", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+	return router
+}
+
+// postHook sends body to /hook with the signature in header, if not empty
+func postHook(router *gin.Engine, header, signature, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/hook", strings.NewReader(body))
+	if signature != "" {
+		req.Header.Set(header, signature)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := `{"event":"push"}`
+	signature := sign(sha256.New, "webhook-secret", body)
+	router := webhookRouter("X-Hub-Signature-256", "sha256")
+
+	tests := []struct {
+		name      string
+		signature string
+		body      string
+		want      int
+	}{
+		{"valid", signature, body, http.StatusOK},
+		{"valid with algo prefix", "sha256=" + signature, body, http.StatusOK},
+		{"tampered body", signature, `{"event":"delete"}`, http.StatusUnauthorized},
+		{"missing header", "", body, http.StatusUnauthorized},
+		{"not hex", "zz-not-hex", body, http.StatusUnauthorized},
+		{"wrong secret", sign(sha256.New, "other-secret", body), body, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postHook(router, "X-Hub-Signature-256", tt.signature, tt.body)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if tt.want == http.StatusOK && w.Body.String() != tt.body {
+				t.Errorf("handler read %q, want the restored body %q", w.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestVerifySignatureCustomHeaderAndAlgo(t *testing.T) {
+	body := "payload"
+	router := webhookRouter("X-Signature", "sha512")
+
+	if w := postHook(router, "X-Signature", sign(sha512.New, "webhook-secret", body), body); w.Code != http.StatusOK {
+		t.Errorf("sha512 signature: status = %d, want 200", w.Code)
+	}
+	if w := postHook(router, "X-Signature", sign(sha256.New, "webhook-secret", body), body); w.Code != http.StatusUnauthorized {
+		t.Errorf("sha256 signature for a sha512 verifier: status = %d, want 401", w.Code)
+	}
+}
+
+func TestVerifySignatureUnsupportedAlgoPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for an unsupported algorithm")
+		}
+	}()
+	VerifySignature("secret", "X-Signature", "md5")
+}
+
+func TestVerifySignatureAlgoCaseInsensitive(t *testing.T) {
+	body := "payload"
+	signature := sign(sha256.New, "webhook-secret", body)
+	router := webhookRouter("X-Signature", "SHA256")
+
+	for _, header := range []string{"sha256=" + signature, "SHA256=" + signature, signature} {
+		if w := postHook(router, "X-Signature", header, body); w.Code != http.StatusOK {
+			t.Errorf("signature %q with algo SHA256: status = %d, want 200", header, w.Code)
+		}
+	}
+}
+
+func TestVerifySignatureBodyLimit(t *testing.T) {
+	router := gin.New()
+	router.Use(VerifySignatureWithLimit("webhook-secret", "X-Signature", "sha256", 16))
+	router.POST("/hook", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	small := "0123456789abcdef"
+	if w := postHook(router, "X-Signature", sign(sha256.New, "webhook-secret", small), small); w.Code != http.StatusOK {
+		t.Errorf("body at the limit: status = %d, want 200", w.Code)
+	}
+	large := small + "!"
+	if w := postHook(router, "X-Signature", sign(sha256.New, "webhook-secret", large), large); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("body over the limit: status = %d, want 413", w.Code)
+	}
+}
+
+func TestVerifySignatureDefaultBodyLimit(t *testing.T) {
+	body := strings.Repeat("x", defaultSignatureMaxBytes+1)
+	w := postHook(webhookRouter("X-Signature", "sha256"), "X-Signature", sign(sha256.New, "webhook-secret", body), body)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("body over 1 MiB: status = %d, want 413", w.Code)
+	}
+}
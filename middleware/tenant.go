@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WorkspaceIDKey is the context key for the workspace (tenant) ID
+const WorkspaceIDKey = "workspace_id"
+
+// WorkspaceIDHeader is the header carrying the workspace ID
+const WorkspaceIDHeader = "X-Workspace-Id"
+
+// workspaceIDContextKey stores the workspace ID on the request context
+type workspaceIDContextKey struct{}
+
+// TenantContext returns a middleware for tenant-scoped route groups that
+// requires a UUID X-Workspace-Id header, responding 400 when it is missing or
+// malformed. The ID is stored under WorkspaceIDKey and on the request context
+// for DB scoping, and is logged by Logger.
+func TenantContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader(WorkspaceIDHeader)
+		if header == "" {
+			response.BadRequest(c, "Missing "+WorkspaceIDHeader+" header")
+			c.Abort()
+			return
+		}
+
+		workspaceID, err := uuid.Parse(header)
+		if err != nil {
+			response.BadRequest(c, "Invalid "+WorkspaceIDHeader+" header: must be a UUID")
+			c.Abort()
+			return
+		}
+
+		id := workspaceID.String()
+		c.Set(WorkspaceIDKey, id)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), workspaceIDContextKey{}, id))
+
+		c.Next()
+	}
+}
+
+// WorkspaceIDFromContext returns the workspace ID stored by TenantContext
+func WorkspaceIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(workspaceIDContextKey{}).(string)
+	return id, ok && id != ""
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// tenantRouter returns a router with /public and a tenant-scoped /scoped group
+// whose handler echoes the workspace ID from the request context
+func tenantRouter(logger *zap.Logger) *gin.Engine {
+	router := gin.New()
+	router.Use(Logger(logger))
+	router.GET("/public", func(c *gin.Context) { c.Status(http.StatusOK) })
+	scoped := router.Group("/scoped", TenantContext())
+	scoped.GET("", func(c *gin.Context) {
+		id, _ := WorkspaceIDFromContext(c.Request.Context())
+		c.String(http.StatusOK, id)
+	})
+	return router
+}
+
+func TestTenantContext(t *testing.T) {
+	const workspaceID = "3f2b8c1e-7a4d-4e8b-9c2a-1d5e6f7a8b9c"
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		want   int
+	}{
+		{"valid workspace", "/scoped", workspaceID, http.StatusOK},
+		{"uppercase is normalized", "/scoped", "3F2B8C1E-7A4D-4E8B-9C2A-1D5E6F7A8B9C", http.StatusOK},
+		{"invalid format", "/scoped", "workspace-1", http.StatusBadRequest},
+		{"missing header", "/scoped", "", http.StatusBadRequest},
+		{"unscoped route", "/public", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, logs := observedLogger()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(WorkspaceIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			tenantRouter(logger).ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if tt.path != "/scoped" || tt.want != http.StatusOK {
+				return
+			}
+			if w.Body.String() != workspaceID {
+				t.Errorf("request context workspace = %q, want %q", w.Body.String(), workspaceID)
+			}
+			if got := onlyEntry(t, logs).ContextMap()["workspace_id"]; got != workspaceID {
+				t.Errorf("logged workspace_id = %v, want %s", got, workspaceID)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// timeoutMessage is the error message sent when a request times out
+const timeoutMessage = "Request timed out"
+
+// Timeout returns a middleware that bounds the request context to d and
+// responds 503 if the handler has not finished by then. The handler's
+// response is buffered and discarded on timeout, so output written after the
+// deadline never reaches the client. The middleware still waits for the
+// handler to return, which it should do promptly once its context is done.
+// skipPaths, e.g. streaming or SSE endpoints, are not bounded or buffered.
+func Timeout(d time.Duration, skipPaths ...string) gin.HandlerFunc {
+	skipMap := make(map[string]bool, len(skipPaths))
+	for _, path := range skipPaths {
+		skipMap[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if skipMap[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		original := c.Writer
+		tw := newTimeoutWriter(original)
+		c.Writer = tw
+
+		// Render the timeout response up front; once the handler is running
+		// the context belongs to its goroutine
+		response.ServiceUnavailable(c, timeoutMessage)
+		timeoutHeader, timeoutStatus, timeoutBody := tw.take()
+
+		done := make(chan struct{})
+		var panicValue interface{}
+		go func() {
+			defer close(done)
+			defer func() {
+				panicValue = recover()
+			}()
+			c.Next()
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+		// A handler that returns because its context expired has still timed
+		// out, even if it won the race with the deadline
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			tw.timeout()
+			c.Set(TimeoutReasonKey, "handler exceeded "+d.String())
+
+			copyHeader(original.Header(), timeoutHeader)
+			original.Header().Set("Content-Length", strconv.Itoa(len(timeoutBody)))
+			original.WriteHeader(timeoutStatus)
+			_, _ = original.Write(timeoutBody)
+			original.Flush()
+		}
+		<-done
+		c.Writer = original
+
+		if tw.timedOut {
+			if panicValue != nil {
+				_ = c.Error(fmt.Errorf("handler panicked after timeout: %v", panicValue))
+			}
+			c.Abort()
+			return
+		}
+		if panicValue != nil {
+			// Let Recovery handle it on the request goroutine
+			panic(panicValue)
+		}
+		tw.flush()
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// timeoutRouter returns a logged router with a 20ms Timeout around /slow,
+// which waits for its context, and /fast
+func timeoutRouter(logger *zap.Logger) *gin.Engine {
+	router := gin.New()
+	router.Use(Logger(logger), Timeout(20*time.Millisecond, "/stream"))
+	router.GET("/slow", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	})
+	router.GET("/fast", func(c *gin.Context) { c.String(http.StatusOK, "done") })
+	router.GET("/stream", func(c *gin.Context) {
+		if _, ok := c.Request.Context().Deadline(); ok {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestTimeoutLogsTimeoutReason(t *testing.T) {
+	logger, logs := observedLogger()
+	w := httptest.NewRecorder()
+	timeoutRouter(logger).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", w.Code)
+	}
+	fields := onlyEntry(t, logs).ContextMap()
+	if fields["timeout"] != true || fields["reason"] != "handler exceeded 20ms" {
+		t.Errorf("fields = %v, want timeout with reason", fields)
+	}
+	if _, ok := fields["client_disconnected"]; ok {
+		t.Error("timeout logged as a client disconnect")
+	}
+}
+
+func TestTimeoutLogsClientDisconnect(t *testing.T) {
+	logger, logs := observedLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil).WithContext(ctx)
+	time.AfterFunc(5*time.Millisecond, cancel)
+
+	timeoutRouter(logger).ServeHTTP(httptest.NewRecorder(), req)
+
+	fields := onlyEntry(t, logs).ContextMap()
+	if fields["client_disconnected"] != true {
+		t.Errorf("fields = %v, want client_disconnected", fields)
+	}
+	if _, ok := fields["timeout"]; ok {
+		t.Error("client disconnect logged as a timeout")
+	}
+}
+
+func TestTimeoutCompletedRequestNotFlagged(t *testing.T) {
+	logger, logs := observedLogger()
+	w := httptest.NewRecorder()
+	timeoutRouter(logger).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fast", nil))
+
+	if w.Code != http.StatusOK || w.Body.String() != "done" {
+		t.Fatalf("got %d %q, want the handler's response", w.Code, w.Body.String())
+	}
+	fields := onlyEntry(t, logs).ContextMap()
+	for _, key := range []string{"timeout", "client_disconnected"} {
+		if _, ok := fields[key]; ok {
+			t.Errorf("%s logged for a request that completed in time", key)
+		}
+	}
+}
+
+func TestTimeoutSkipPaths(t *testing.T) {
+	logger, _ := observedLogger()
+	w := httptest.NewRecorder()
+	timeoutRouter(logger).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 without a deadline on skipped paths", w.Code)
+	}
+}
@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/config"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Semantic convention attributes set on server spans
+const (
+	httpRouteKey      = attribute.Key("http.route")
+	httpMethodKey     = attribute.Key("http.request.method")
+	httpStatusCodeKey = attribute.Key("http.response.status_code")
+	urlPathKey        = attribute.Key("url.path")
+	serviceNameKey    = attribute.Key("service.name")
+)
+
+const (
+	// TraceIDKey is the gin context key Tracing stores the trace ID under
+	TraceIDKey = "trace_id"
+	// SpanIDKey is the gin context key Tracing stores the server span ID under
+	SpanIDKey = "span_id"
+)
+
+// traceContext extracts W3C traceparent/tracestate and baggage headers
+var traceContext = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Tracing returns a middleware that continues the trace from an incoming W3C
+// traceparent header, or starts a new one, with a server span per request
+// from the global tracer provider. The span carries the http.route attribute
+// RouteSampler uses, and its IDs are stored under TraceIDKey and SpanIDKey
+// and on the request context, where Logger picks them up.
+func Tracing(serviceName string) gin.HandlerFunc {
+	tracer := otel.Tracer("github.com/OrangesCloud/wealist-advanced-go-pkg/middleware")
+
+	return func(c *gin.Context) {
+		ctx := traceContext.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		spanName := c.Request.Method
+		attrs := []attribute.KeyValue{
+			httpMethodKey.String(c.Request.Method),
+			urlPathKey.String(c.Request.URL.Path),
+			serviceNameKey.String(serviceName),
+		}
+		if route != "" {
+			spanName += " " + route
+			attrs = append(attrs, httpRouteKey.String(route))
+		}
+
+		ctx, span := tracer.Start(ctx, spanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attrs...),
+		)
+		defer span.End()
+
+		if spanContext := span.SpanContext(); spanContext.IsValid() {
+			c.Set(TraceIDKey, spanContext.TraceID().String())
+			c.Set(SpanIDKey, spanContext.SpanID().String())
+		}
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(httpStatusCodeKey.Int(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		for _, err := range c.Errors {
+			span.RecordError(err.Err)
+		}
+	}
+}
+
+// routeSampler samples root spans by the ratio configured for their
+// http.route attribute, falling back to a global ratio
+type routeSampler struct {
+	fallback sdktrace.Sampler
+	routes   map[string]sdktrace.Sampler
+}
+
+// RouteSampler returns a sampler that applies the ratio in routes for spans
+// whose http.route attribute (the gin c.FullPath() pattern) has an entry, and
+// ratio for all others. The attribute must be set when the span starts, as
+// the Tracing middleware does. Use 1 to always sample a route and 0 to never.
+func RouteSampler(ratio float64, routes map[string]float64) sdktrace.Sampler {
+	samplers := make(map[string]sdktrace.Sampler, len(routes))
+	for route, routeRatio := range routes {
+		samplers[route] = sdktrace.TraceIDRatioBased(routeRatio)
+	}
+	return &routeSampler{fallback: sdktrace.TraceIDRatioBased(ratio), routes: samplers}
+}
+
+// SamplerFromConfig returns a parent-based RouteSampler for the tracing config,
+// so spans of requests that arrive already sampled stay sampled. Route ratios
+// of 0 and 1 are applied before the parent's decision, so a route set to 0,
+// such as a mesh health check, is never traced even with a sampled traceparent.
+func SamplerFromConfig(cfg config.TracingConfig) sdktrace.Sampler {
+	forced := make(map[string]sdktrace.Sampler)
+	for route, ratio := range cfg.RouteSampleRatios {
+		switch {
+		case ratio <= 0:
+			forced[route] = sdktrace.NeverSample()
+		case ratio >= 1:
+			forced[route] = sdktrace.AlwaysSample()
+		}
+	}
+	parentBased := sdktrace.ParentBased(RouteSampler(cfg.SampleRatio, cfg.RouteSampleRatios))
+	if len(forced) == 0 {
+		return parentBased
+	}
+	return &forcedRouteSampler{forced: forced, next: parentBased}
+}
+
+// spanRoute returns the http.route attribute of the span being sampled
+func spanRoute(p sdktrace.SamplingParameters) (string, bool) {
+	for _, attr := range p.Attributes {
+		if attr.Key == httpRouteKey {
+			return attr.Value.AsString(), true
+		}
+	}
+	return "", false
+}
+
+// ShouldSample delegates to the sampler for the span's route
+func (s *routeSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if route, ok := spanRoute(p); ok {
+		if sampler, ok := s.routes[route]; ok {
+			return sampler.ShouldSample(p)
+		}
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+// Description returns a description of the sampler
+func (s *routeSampler) Description() string {
+	return fmt.Sprintf("RouteSampler{fallback:%s,routes:%d}", s.fallback.Description(), len(s.routes))
+}
+
+// forcedRouteSampler always or never samples the routes in forced, whatever
+// the parent decided, and leaves every other span to next
+type forcedRouteSampler struct {
+	forced map[string]sdktrace.Sampler
+	next   sdktrace.Sampler
+}
+
+// ShouldSample applies the forced decision for the span's route, if any
+func (s *forcedRouteSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if route, ok := spanRoute(p); ok {
+		if sampler, ok := s.forced[route]; ok {
+			return sampler.ShouldSample(p)
+		}
+	}
+	return s.next.ShouldSample(p)
+}
+
+// Description returns a description of the sampler
+func (s *forcedRouteSampler) Description() string {
+	return fmt.Sprintf("ForcedRouteSampler{forced:%d,next:%s}", len(s.forced), s.next.Description())
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/config"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// tracedRouter installs a global tracer provider using sampler, returning a
+// router with Tracing and the recorder collecting its ended spans
+func tracedRouter(t *testing.T, sampler sdktrace.Sampler, paths ...string) (*gin.Engine, *tracetest.SpanRecorder) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler), sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(t.Context())
+	})
+
+	router := gin.New()
+	router.Use(Tracing("orders"))
+	for _, path := range paths {
+		router.GET(path, func(c *gin.Context) { c.Status(http.StatusOK) })
+	}
+	return router, recorder
+}
+
+// spansFor serves n requests to target and returns how many spans ended for them
+func spansFor(router *gin.Engine, recorder *tracetest.SpanRecorder, target string, n int) int {
+	before := len(recorder.Ended())
+	for i := 0; i < n; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+	return len(recorder.Ended()) - before
+}
+
+func TestRouteSamplerOverridesGlobalRatio(t *testing.T) {
+	sampler := SamplerFromConfig(config.TracingConfig{
+		SampleRatio: 0.01,
+		RouteSampleRatios: map[string]float64{
+			"/checkout/:id": 1,
+			"/health":       0,
+		},
+	})
+	router, recorder := tracedRouter(t, sampler, "/checkout/:id", "/health", "/items")
+
+	if n := spansFor(router, recorder, "/checkout/42", 20); n != 20 {
+		t.Errorf("always-sampled route: %d of 20 spans, want 20", n)
+	}
+	if n := spansFor(router, recorder, "/health", 20); n != 0 {
+		t.Errorf("never-sampled route: %d of 20 spans, want 0", n)
+	}
+	if n := spansFor(router, recorder, "/items", 200); n > 20 {
+		t.Errorf("route at the 1%% global ratio: %d of 200 spans", n)
+	}
+}
+
+// sendWithParent serves target with a traceparent header whose sampled flag is flags
+func sendWithParent(router *gin.Engine, target, flags string) {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-"+flags)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestRouteSamplerKeepsSampledParent(t *testing.T) {
+	sampler := SamplerFromConfig(config.TracingConfig{RouteSampleRatios: map[string]float64{"/items": 0.5}})
+	router, recorder := tracedRouter(t, sampler, "/items", "/orders")
+
+	for _, target := range []string{"/items", "/orders"} {
+		sendWithParent(router, target, "01")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want the sampled parent to be followed on both routes", len(spans))
+	}
+	if got := spans[0].SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %s, want the incoming trace", got)
+	}
+	if spans[0].Name() != "GET /items" {
+		t.Errorf("span name = %q, want GET /items", spans[0].Name())
+	}
+}
+
+func TestRouteSamplerForcedRatiosOverrideParent(t *testing.T) {
+	sampler := SamplerFromConfig(config.TracingConfig{
+		SampleRatio:       0.5,
+		RouteSampleRatios: map[string]float64{"/health": 0, "/checkout": 1},
+	})
+	router, recorder := tracedRouter(t, sampler, "/health", "/checkout", "/items")
+
+	sendWithParent(router, "/health", "01")
+	if n := len(recorder.Ended()); n != 0 {
+		t.Fatalf("route at ratio 0 with a sampled parent: %d spans, want 0", n)
+	}
+
+	sendWithParent(router, "/checkout", "00")
+	if n := len(recorder.Ended()); n != 1 {
+		t.Fatalf("route at ratio 1 with an unsampled parent: %d spans, want 1", n)
+	}
+
+	sendWithParent(router, "/items", "00")
+	if n := len(recorder.Ended()); n != 1 {
+		t.Errorf("unforced route with an unsampled parent: %d spans in total, want 1 as the parent is followed", n)
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRecordedBody bounds each request and response body kept by RecordTraffic
+const maxRecordedBody = 64 << 10
+
+// Interaction is a recorded request/response pair with sensitive values masked
+type Interaction struct {
+	Method       string
+	Path         string
+	Query        string
+	RequestBody  string
+	Status       int
+	ResponseBody string
+	RequestID    string
+	RecordedAt   time.Time
+}
+
+// TrafficSink receives recorded interactions, e.g. to build contract-test fixtures
+type TrafficSink interface {
+	Record(interaction Interaction)
+}
+
+// RecordTraffic returns a middleware that records method, path, status and
+// request and response bodies for a sampleRate fraction (0-1) of requests and
+// hands them to sink. Bodies are truncated to 64KiB and masked like logged
+// bodies. A sampleRate of 0 or a nil sink disables recording. The sink is called
+// on the request goroutine, so it should not block.
+func RecordTraffic(sink TrafficSink, sampleRate float64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if sink == nil || sampleRate <= 0 || (sampleRate < 1 && rand.Float64() >= sampleRate) {
+			c.Next()
+			return
+		}
+
+		var requestBody []byte
+		if body := c.Request.Body; body != nil && body != http.NoBody {
+			requestBody, _ = io.ReadAll(io.LimitReader(body, maxRecordedBody))
+			c.Request.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(requestBody), body), body}
+		}
+		requestContentType := c.ContentType()
+
+		writer := &teeWriter{ResponseWriter: c.Writer, limit: maxRecordedBody}
+		c.Writer = writer
+
+		c.Next()
+
+		sink.Record(Interaction{
+			Method:       c.Request.Method,
+			Path:         c.Request.URL.Path,
+			Query:        c.Request.URL.RawQuery,
+			RequestBody:  maskBody(requestBody, requestContentType),
+			Status:       writer.Status(),
+			ResponseBody: maskBody(writer.body.Bytes(), writer.Header().Get("Content-Type")),
+			RequestID:    c.GetString(RequestIDKey),
+			RecordedAt:   time.Now(),
+		})
+	}
+}
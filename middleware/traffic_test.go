@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeSink collects recorded interactions
+type fakeSink struct {
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+func (s *fakeSink) Record(interaction Interaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.interactions = append(s.interactions, interaction)
+}
+
+// trafficRouter returns a router recording into sink, with /login echoing the
+// request body and returning a token
+func trafficRouter(sink TrafficSink, sampleRate float64) *gin.Engine {
+	router := gin.New()
+	router.Use(RequestContext(), RecordTraffic(sink, sampleRate))
+	router.POST("/login", func(c *gin.Context) {
+		var body map[string]string
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"user": body["username"], "token": "tok-123"})
+	})
+	return router
+}
+
+// postLogin sends a JSON login request to router
+func postLogin(router *gin.Engine) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/login?next=/home", strings.NewReader(`{"username":"alice","password":"hunter2"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRecordTrafficRecordsSampledRequest(t *testing.T) {
+	sink := &fakeSink{}
+	w := postLogin(trafficRouter(sink, 1))
+
+	if w.Code != http.StatusCreated || !strings.Contains(w.Body.String(), "tok-123") {
+		t.Fatalf("got %d %s, want the handler's response unchanged", w.Code, w.Body.String())
+	}
+	if len(sink.interactions) != 1 {
+		t.Fatalf("recorded %d interactions, want 1", len(sink.interactions))
+	}
+	got := sink.interactions[0]
+	if got.Method != http.MethodPost || got.Path != "/login" || got.Query != "next=/home" || got.Status != http.StatusCreated {
+		t.Errorf("interaction = %+v", got)
+	}
+	if !strings.Contains(got.RequestBody, `"username":"alice"`) || strings.Contains(got.RequestBody, "hunter2") {
+		t.Errorf("request body = %s, want the password masked", got.RequestBody)
+	}
+	if !strings.Contains(got.ResponseBody, `"user":"alice"`) || strings.Contains(got.ResponseBody, "tok-123") {
+		t.Errorf("response body = %s, want the token masked", got.ResponseBody)
+	}
+	if got.RequestID == "" || got.RecordedAt.IsZero() {
+		t.Errorf("request ID %q, recorded at %v, want both set", got.RequestID, got.RecordedAt)
+	}
+}
+
+func TestRecordTrafficDisabled(t *testing.T) {
+	sink := &fakeSink{}
+	router := trafficRouter(sink, 0)
+	for i := 0; i < 10; i++ {
+		if w := postLogin(router); w.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want 201", w.Code)
+		}
+	}
+	if len(sink.interactions) != 0 {
+		t.Errorf("recorded %d interactions with a 0 sample rate", len(sink.interactions))
+	}
+
+	if w := postLogin(trafficRouter(nil, 1)); w.Code != http.StatusCreated {
+		t.Errorf("nil sink: status = %d, want 201", w.Code)
+	}
+}
+
+func TestRecordTrafficSamplesFraction(t *testing.T) {
+	sink := &fakeSink{}
+	router := trafficRouter(sink, 0.5)
+	for i := 0; i < 400; i++ {
+		postLogin(router)
+	}
+	if n := len(sink.interactions); n < 120 || n > 280 {
+		t.Errorf("recorded %d of 400 at a 0.5 sample rate", n)
+	}
+}
+
+func TestRecordTrafficBoundsBodies(t *testing.T) {
+	sink := &fakeSink{}
+	router := gin.New()
+	router.Use(RecordTraffic(sink, 1))
+	large := strings.Repeat("x", 2*maxRecordedBody)
+	router.POST("/upload", func(c *gin.Context) {
+		body, _ := c.GetRawData()
+		c.String(http.StatusOK, "%d", len(body))
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(large))
+	req.Header.Set("Content-Type", "text/plain")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Body.String() != strconv.Itoa(len(large)) {
+		t.Fatalf("handler read %s bytes, want the full body", w.Body.String())
+	}
+	if got := sink.interactions[0].RequestBody; len(got) > 100 || !strings.Contains(got, strconv.Itoa(maxRecordedBody)+" bytes") {
+		t.Errorf("request body = %.100q, want a bounded placeholder", got)
+	}
+}
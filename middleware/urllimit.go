@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// URLLimit returns a middleware that rejects requests whose path or raw query
+// exceeds the given lengths with 414 URI Too Long. A limit of 0 disables that check.
+// Register it on the engine so it runs before route handlers.
+func URLLimit(maxPathLen, maxQueryLen int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if (maxPathLen > 0 && len(c.Request.URL.Path) > maxPathLen) ||
+			(maxQueryLen > 0 && len(c.Request.URL.RawQuery) > maxQueryLen) {
+			response.Error(c, http.StatusRequestURITooLong, "URI_TOO_LONG", "Request URI too long")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+func TestURLLimit(t *testing.T) {
+	router := gin.New()
+	router.Use(URLLimit(64, 32))
+	router.GET("/*path", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name   string
+		target string
+		want   int
+	}{
+		{"normal", "/items?page=1", http.StatusOK},
+		{"query at limit", "/items?q=" + strings.Repeat("a", 30), http.StatusOK},
+		{"oversized query", "/items?q=" + strings.Repeat("a", 100), http.StatusRequestURITooLong},
+		{"oversized path", "/" + strings.Repeat("p", 100), http.StatusRequestURITooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if tt.want != http.StatusRequestURITooLong {
+				return
+			}
+
+			var body response.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if body.Error.Code != "URI_TOO_LONG" || body.RequestID == "" {
+				t.Errorf("body = %+v, want the standard error envelope", body)
+			}
+		})
+	}
+}
+
+func TestURLLimitZeroDisablesCheck(t *testing.T) {
+	router := gin.New()
+	router.Use(URLLimit(0, 0))
+	router.GET("/items", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items?q="+strings.Repeat("a", 10000), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 with limits disabled", w.Code)
+	}
+}
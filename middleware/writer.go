@@ -0,0 +1,255 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bufferedWriter holds back the status and body written by downstream handlers
+// so a middleware can inspect or replace them before anything reaches the client
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	status    int
+	committed bool
+}
+
+func newBufferedWriter(w gin.ResponseWriter) *bufferedWriter {
+	return &bufferedWriter{ResponseWriter: w, status: http.StatusOK}
+}
+
+// WriteHeader records the status without sending it
+func (w *bufferedWriter) WriteHeader(code int) {
+	if code > 0 {
+		w.status = code
+	}
+}
+
+// WriteHeaderNow marks the header as written without sending it
+func (w *bufferedWriter) WriteHeaderNow() {
+	w.committed = true
+}
+
+// Write appends to the buffer
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.committed = true
+	return w.body.Write(data)
+}
+
+// WriteString appends to the buffer
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.committed = true
+	return w.body.WriteString(s)
+}
+
+// Status returns the buffered status code
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+// Size returns the number of buffered body bytes
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+// Written reports whether the handler has written a header or body
+func (w *bufferedWriter) Written() bool {
+	return w.committed
+}
+
+// Flush is a no-op; buffered output is only sent by flush
+func (w *bufferedWriter) Flush() {}
+
+// flush sends the buffered status and body to the underlying writer
+func (w *bufferedWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.body.Len() == 0 {
+		w.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	_, _ = w.ResponseWriter.Write(w.body.Bytes())
+}
+
+// ttfbWriter records when the first response byte is sent. Gin defers the
+// status line until the first write, so WriteHeader alone does not count.
+type ttfbWriter struct {
+	gin.ResponseWriter
+	firstByte time.Time
+}
+
+func (w *ttfbWriter) mark() {
+	if w.firstByte.IsZero() {
+		w.firstByte = time.Now()
+	}
+}
+
+// WriteHeaderNow records the first byte time and sends the header
+func (w *ttfbWriter) WriteHeaderNow() {
+	w.mark()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Write records the first byte time and writes data
+func (w *ttfbWriter) Write(data []byte) (int, error) {
+	w.mark()
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString records the first byte time and writes s
+func (w *ttfbWriter) WriteString(s string) (int, error) {
+	w.mark()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Flush records the first byte time and flushes the response
+func (w *ttfbWriter) Flush() {
+	w.mark()
+	w.ResponseWriter.Flush()
+}
+
+// teeWriter passes the response through while keeping a copy of up to limit body bytes
+type teeWriter struct {
+	gin.ResponseWriter
+	body  bytes.Buffer
+	limit int
+}
+
+// Write copies data up to the limit and writes it through
+func (w *teeWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString copies s up to the limit and writes it through
+func (w *teeWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *teeWriter) capture(data []byte) {
+	if remaining := w.limit - w.body.Len(); remaining > 0 {
+		if len(data) > remaining {
+			data = data[:remaining]
+		}
+		w.body.Write(data)
+	}
+}
+
+// timeoutWriter buffers a handler's response like bufferedWriter but with its
+// own header map and a lock, so the handler can keep writing from its
+// goroutine after Timeout has answered. Writes after the timeout are dropped.
+type timeoutWriter struct {
+	gin.ResponseWriter
+	mu        sync.Mutex
+	header    http.Header
+	body      bytes.Buffer
+	status    int
+	committed bool
+	timedOut  bool
+}
+
+func newTimeoutWriter(w gin.ResponseWriter) *timeoutWriter {
+	return &timeoutWriter{ResponseWriter: w, header: make(http.Header), status: http.StatusOK}
+}
+
+// Header returns the buffered header map
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader records the status without sending it
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if code > 0 && !w.timedOut {
+		w.status = code
+	}
+}
+
+// WriteHeaderNow marks the header as written without sending it
+func (w *timeoutWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.committed = true
+}
+
+// Write appends to the buffer, failing once the request has timed out
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	w.committed = true
+	return w.body.Write(data)
+}
+
+// WriteString appends to the buffer, failing once the request has timed out
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Status returns the buffered status code
+func (w *timeoutWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
+
+// Size returns the number of buffered body bytes
+func (w *timeoutWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.body.Len()
+}
+
+// Written reports whether the handler has written a header or body
+func (w *timeoutWriter) Written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.committed
+}
+
+// Flush is a no-op; buffered output is only sent by flush
+func (w *timeoutWriter) Flush() {}
+
+// take returns the buffered response and resets the writer
+func (w *timeoutWriter) take() (http.Header, int, []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	header, status, body := w.header, w.status, append([]byte(nil), w.body.Bytes()...)
+	w.header, w.status, w.committed = make(http.Header), http.StatusOK, false
+	w.body.Reset()
+	return header, status, body
+}
+
+// timeout drops the handler's response and any later writes
+func (w *timeoutWriter) timeout() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.timedOut = true
+}
+
+// flush sends the buffered header, status and body to the underlying writer
+func (w *timeoutWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	copyHeader(w.ResponseWriter.Header(), w.header)
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.body.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+	} else if w.committed {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+// copyHeader adds the values of src to dst, replacing existing keys
+func copyHeader(dst, src http.Header) {
+	for key, values := range src {
+		dst[key] = values
+	}
+}
@@ -0,0 +1,117 @@
+package response
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorCode is a machine-readable error code sent in the error envelope
+type ErrorCode string
+
+// Error codes used by the built-in helpers
+const (
+	CodeBadRequest           ErrorCode = "BAD_REQUEST"
+	CodeUnauthorized         ErrorCode = "UNAUTHORIZED"
+	CodeForbidden            ErrorCode = "FORBIDDEN"
+	CodeNotFound             ErrorCode = "NOT_FOUND"
+	CodeMethodNotAllowed     ErrorCode = "METHOD_NOT_ALLOWED"
+	CodeConflict             ErrorCode = "CONFLICT"
+	CodeGone                 ErrorCode = "GONE"
+	CodePreconditionFailed   ErrorCode = "PRECONDITION_FAILED"
+	CodePreconditionRequired ErrorCode = "PRECONDITION_REQUIRED"
+	CodeValidationError      ErrorCode = "VALIDATION_ERROR"
+	CodeUnprocessableEntity  ErrorCode = "UNPROCESSABLE_ENTITY"
+	CodeTooManyRequests      ErrorCode = "TOO_MANY_REQUESTS"
+	CodeInternalError        ErrorCode = "INTERNAL_ERROR"
+	CodeNotImplemented       ErrorCode = "NOT_IMPLEMENTED"
+	CodeServiceUnavailable   ErrorCode = "SERVICE_UNAVAILABLE"
+)
+
+// errorCodeInfo is the registered status and message of an error code
+type errorCodeInfo struct {
+	status  int
+	message string
+}
+
+var (
+	errorCodesMu sync.RWMutex
+	errorCodes   = map[ErrorCode]errorCodeInfo{
+		CodeBadRequest:           {http.StatusBadRequest, "Bad request"},
+		CodeUnauthorized:         {http.StatusUnauthorized, "Unauthorized"},
+		CodeForbidden:            {http.StatusForbidden, "Forbidden"},
+		CodeNotFound:             {http.StatusNotFound, "Not found"},
+		CodeMethodNotAllowed:     {http.StatusMethodNotAllowed, "Method not allowed"},
+		CodeConflict:             {http.StatusConflict, "Conflict"},
+		CodeGone:                 {http.StatusGone, "Gone"},
+		CodePreconditionFailed:   {http.StatusPreconditionFailed, "Precondition failed"},
+		CodePreconditionRequired: {http.StatusPreconditionRequired, "Precondition required"},
+		CodeValidationError:      {http.StatusBadRequest, "Validation failed"},
+		CodeUnprocessableEntity:  {http.StatusUnprocessableEntity, "Unprocessable entity"},
+		CodeTooManyRequests:      {http.StatusTooManyRequests, "Too many requests"},
+		CodeInternalError:        {http.StatusInternalServerError, "Internal server error"},
+		CodeNotImplemented:       {http.StatusNotImplemented, "Not implemented"},
+		CodeServiceUnavailable:   {http.StatusServiceUnavailable, "Service unavailable"},
+	}
+)
+
+// RegisterErrorCode registers the HTTP status and default message for a
+// domain error code, e.g. ErrWorkspaceNotFound. Registering a code again replaces it.
+func RegisterErrorCode(code ErrorCode, httpStatus int, defaultMessage string) {
+	errorCodesMu.Lock()
+	defer errorCodesMu.Unlock()
+	errorCodes[code] = errorCodeInfo{status: httpStatus, message: defaultMessage}
+}
+
+// ErrorFromCode sends an error response with the status and default message
+// registered for code. Unregistered codes are sent as 500.
+func ErrorFromCode(c *gin.Context, code ErrorCode) {
+	errorCodesMu.RLock()
+	info, ok := errorCodes[code]
+	errorCodesMu.RUnlock()
+
+	if !ok {
+		info = errorCodeInfo{status: http.StatusInternalServerError, message: http.StatusText(http.StatusInternalServerError)}
+	}
+	Error(c, info.status, string(code), info.message)
+}
+
+// statusCodes maps statuses to the codes sent by the built-in helpers
+var statusCodes = map[int]ErrorCode{
+	http.StatusBadRequest:           CodeBadRequest,
+	http.StatusUnauthorized:         CodeUnauthorized,
+	http.StatusForbidden:            CodeForbidden,
+	http.StatusNotFound:             CodeNotFound,
+	http.StatusMethodNotAllowed:     CodeMethodNotAllowed,
+	http.StatusConflict:             CodeConflict,
+	http.StatusGone:                 CodeGone,
+	http.StatusPreconditionFailed:   CodePreconditionFailed,
+	http.StatusPreconditionRequired: CodePreconditionRequired,
+	http.StatusUnprocessableEntity:  CodeUnprocessableEntity,
+	http.StatusTooManyRequests:      CodeTooManyRequests,
+	http.StatusInternalServerError:  CodeInternalError,
+	http.StatusNotImplemented:       CodeNotImplemented,
+	http.StatusServiceUnavailable:   CodeServiceUnavailable,
+}
+
+// CodeForStatus returns the error code the built-in helpers use for status.
+// Other statuses get their status text in upper snake case (e.g. 418
+// IM_A_TEAPOT), and unknown statuses get "ERROR".
+func CodeForStatus(status int) ErrorCode {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	text := http.StatusText(status)
+	if text == "" {
+		return "ERROR"
+	}
+	text = strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text)
+	return ErrorCode(strings.ToUpper(text))
+}
+
+// ErrorForStatus sends an error response with the standard code for status
+func ErrorForStatus(c *gin.Context, status int, message string) {
+	Error(c, status, string(CodeForStatus(status)), message)
+}
@@ -0,0 +1,77 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCodeForStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   ErrorCode
+	}{
+		{http.StatusBadRequest, CodeBadRequest},
+		{http.StatusUnauthorized, CodeUnauthorized},
+		{http.StatusForbidden, CodeForbidden},
+		{http.StatusNotFound, CodeNotFound},
+		{http.StatusConflict, CodeConflict},
+		{http.StatusUnprocessableEntity, CodeUnprocessableEntity},
+		{http.StatusTooManyRequests, CodeTooManyRequests},
+		{http.StatusInternalServerError, CodeInternalError},
+		{http.StatusServiceUnavailable, CodeServiceUnavailable},
+		{http.StatusTeapot, "IM_A_TEAPOT"},
+		{http.StatusRequestEntityTooLarge, "REQUEST_ENTITY_TOO_LARGE"},
+		{599, "ERROR"},
+	}
+	for _, tt := range tests {
+		if got := CodeForStatus(tt.status); got != tt.want {
+			t.Errorf("CodeForStatus(%d) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestErrorForStatus(t *testing.T) {
+	for _, status := range []int{http.StatusForbidden, http.StatusTooManyRequests, http.StatusBadGateway} {
+		c, w := newTestContext()
+		ErrorForStatus(c, status, "something went wrong")
+
+		if w.Code != status {
+			t.Errorf("status = %d, want %d", w.Code, status)
+		}
+		var body ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if body.Error.Code != string(CodeForStatus(status)) || body.Error.Message != "something went wrong" {
+			t.Errorf("%d: error = %+v, want code %s", status, body.Error, CodeForStatus(status))
+		}
+	}
+}
+
+func TestErrorForStatusMatchesHelpers(t *testing.T) {
+	helpers := map[int]func(c *gin.Context){
+		http.StatusNotFound:     func(c *gin.Context) { NotFound(c, "x") },
+		http.StatusUnauthorized: func(c *gin.Context) { Unauthorized(c, "x") },
+		http.StatusConflict:     func(c *gin.Context) { Conflict(c, "x") },
+
+		http.StatusPreconditionFailed:   func(c *gin.Context) { PreconditionFailed(c, "x") },
+		http.StatusPreconditionRequired: func(c *gin.Context) { PreconditionRequired(c, "x") },
+	}
+	for status, helper := range helpers {
+		c, w := newTestContext()
+		helper(c)
+		if w.Code != status {
+			t.Errorf("helper for %d responds %d", status, w.Code)
+		}
+		var body ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if body.Error.Code != string(CodeForStatus(status)) {
+			t.Errorf("helper for %d sends %q, ErrorForStatus sends %q", status, body.Error.Code, CodeForStatus(status))
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package response
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OKWithLastModified sends a 200 OK response with a Last-Modified header, or a
+// bodiless 304 Not Modified when If-Modified-Since is at or after modTime.
+// If-Modified-Since is ignored when the request carries If-None-Match, which
+// takes precedence (RFC 7232) and is left to ETag handling.
+func OKWithLastModified(c *gin.Context, modTime time.Time, data interface{}) {
+	// HTTP dates have second precision
+	modTime = modTime.UTC().Truncate(time.Second)
+	c.Header("Last-Modified", modTime.Format(http.TimeFormat))
+
+	if isConditionalMethod(c) && c.GetHeader("If-None-Match") == "" {
+		if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && !since.Before(modTime) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+
+	OK(c, data)
+}
+
+// isConditionalMethod reports whether conditional GET semantics apply
+func isConditionalMethod(c *gin.Context) bool {
+	return c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead
+}
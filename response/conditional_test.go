@@ -0,0 +1,62 @@
+package response
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestOKWithLastModifiedNotModified(t *testing.T) {
+	modTime := time.Date(2024, 3, 1, 12, 0, 0, 500, time.UTC)
+
+	for _, since := range []time.Time{modTime, modTime.Add(time.Hour)} {
+		c, w := newTestContext()
+		c.Request.Header.Set("If-Modified-Since", since.Format(http.TimeFormat))
+		OKWithLastModified(c, modTime, map[string]string{"name": "alice"})
+		c.Writer.WriteHeaderNow()
+
+		if w.Code != http.StatusNotModified {
+			t.Errorf("If-Modified-Since %s: status = %d, want 304", since, w.Code)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("304 has body %q", w.Body.String())
+		}
+		if got := w.Header().Get("Last-Modified"); got != modTime.Format(http.TimeFormat) {
+			t.Errorf("Last-Modified = %q", got)
+		}
+	}
+}
+
+func TestOKWithLastModifiedModified(t *testing.T) {
+	modTime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, header := range []string{"", modTime.Add(-time.Second).Format(http.TimeFormat), "not a date"} {
+		c, w := newTestContext()
+		if header != "" {
+			c.Request.Header.Set("If-Modified-Since", header)
+		}
+		OKWithLastModified(c, modTime, map[string]string{"name": "alice"})
+
+		if w.Code != http.StatusOK {
+			t.Errorf("If-Modified-Since %q: status = %d, want 200", header, w.Code)
+		}
+		if got := w.Header().Get("Last-Modified"); got != "Fri, 01 Mar 2024 12:00:00 GMT" {
+			t.Errorf("Last-Modified = %q", got)
+		}
+		if w.Body.Len() == 0 {
+			t.Error("200 has no body")
+		}
+	}
+}
+
+func TestOKWithLastModifiedDefersToIfNoneMatch(t *testing.T) {
+	modTime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	c, w := newTestContext()
+	c.Request.Header.Set("If-Modified-Since", modTime.Format(http.TimeFormat))
+	c.Request.Header.Set("If-None-Match", `"v2"`)
+	OKWithLastModified(c, modTime, nil)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 when If-None-Match is present", w.Code)
+	}
+}
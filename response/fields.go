@@ -0,0 +1,59 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OKWithFields sends a 200 OK response containing only the requested top-level
+// fields of data, matched against its JSON field names (e.g. from ?fields=id,name).
+// A list of objects is filtered per element. Unknown fields are ignored and an
+// empty fields list returns data unchanged.
+func OKWithFields(c *gin.Context, data interface{}, fields []string) {
+	if len(fields) == 0 {
+		OK(c, data)
+		return
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		InternalError(c, "Failed to encode response")
+		return
+	}
+
+	keep := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		keep[field] = true
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err == nil && object != nil {
+		OK(c, filterFields(object, keep))
+		return
+	}
+
+	var list []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &list); err == nil && list != nil {
+		filtered := make([]map[string]json.RawMessage, len(list))
+		for i, item := range list {
+			filtered[i] = filterFields(item, keep)
+		}
+		OK(c, filtered)
+		return
+	}
+
+	// Not an object or list of objects, nothing to filter
+	OK(c, data)
+}
+
+// filterFields returns the entries of object whose keys are in keep
+func filterFields(object map[string]json.RawMessage, keep map[string]bool) map[string]json.RawMessage {
+	filtered := make(map[string]json.RawMessage, len(keep))
+	for key, value := range object {
+		if keep[key] {
+			filtered[key] = value
+		}
+	}
+	return filtered
+}
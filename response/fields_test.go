@@ -0,0 +1,76 @@
+package response
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type fieldsUser struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// decodeData decodes the data field of a JSON envelope into v
+func decodeData(t *testing.T, body []byte, v interface{}) {
+	t.Helper()
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if err := json.Unmarshal(envelope.Data, v); err != nil {
+		t.Fatalf("invalid data: %v", err)
+	}
+}
+
+func TestOKWithFieldsKeepsRequestedFields(t *testing.T) {
+	c, w := newTestContext()
+	OKWithFields(c, fieldsUser{ID: 1, Name: "alice", Email: "alice@example.com"}, []string{"id", "name", "unknown"})
+
+	var data map[string]interface{}
+	decodeData(t, w.Body.Bytes(), &data)
+	if len(data) != 2 || data["id"] != float64(1) || data["name"] != "alice" {
+		t.Errorf("data = %v, want only id and name", data)
+	}
+}
+
+func TestOKWithFieldsEmptyListReturnsEverything(t *testing.T) {
+	c, w := newTestContext()
+	OKWithFields(c, fieldsUser{ID: 1, Name: "alice", Email: "alice@example.com"}, nil)
+
+	var data map[string]interface{}
+	decodeData(t, w.Body.Bytes(), &data)
+	if len(data) != 3 {
+		t.Errorf("data = %v, want all fields", data)
+	}
+}
+
+func TestOKWithFieldsFiltersListElements(t *testing.T) {
+	c, w := newTestContext()
+	users := []fieldsUser{{ID: 1, Name: "alice", Email: "a@example.com"}, {ID: 2, Name: "bob", Email: "b@example.com"}}
+	OKWithFields(c, users, []string{"name"})
+
+	var data []map[string]interface{}
+	decodeData(t, w.Body.Bytes(), &data)
+	if len(data) != 2 {
+		t.Fatalf("got %d items, want 2", len(data))
+	}
+	for i, item := range data {
+		if len(item) != 1 || item["name"] != users[i].Name {
+			t.Errorf("item %d = %v, want only name", i, item)
+		}
+	}
+}
+
+func TestOKWithFieldsNonObjectUnchanged(t *testing.T) {
+	c, w := newTestContext()
+	OKWithFields(c, "plain", []string{"id"})
+
+	var data string
+	decodeData(t, w.Body.Bytes(), &data)
+	if data != "plain" {
+		t.Errorf("data = %q, want plain", data)
+	}
+}
@@ -0,0 +1,133 @@
+package response
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	catalogMu     sync.RWMutex
+	catalog       = make(map[string]map[string]string) // code -> locale -> message
+	defaultLocale = "en"
+)
+
+// RegisterMessage registers the message for an error code in the given locale (e.g. "en", "ko")
+func RegisterMessage(code, locale, message string) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+
+	locale = strings.ToLower(locale)
+	if catalog[code] == nil {
+		catalog[code] = make(map[string]string)
+	}
+	catalog[code][locale] = message
+}
+
+// RegisterMessages registers messages for several error codes in one locale
+func RegisterMessages(locale string, messages map[string]string) {
+	for code, message := range messages {
+		RegisterMessage(code, locale, message)
+	}
+}
+
+// SetDefaultLocale sets the locale used when Accept-Language matches no registered message
+func SetDefaultLocale(locale string) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	defaultLocale = strings.ToLower(locale)
+}
+
+// Fail sends an error response with the message for code taken from the catalog
+// in the language requested by Accept-Language, falling back to the default locale
+func Fail(c *gin.Context, statusCode int, code string) {
+	message, ok := lookupMessage(code, []string{defaultLocaleName()})
+	if !ok {
+		message = http.StatusText(statusCode)
+	}
+	// Error applies the Accept-Language translation
+	Error(c, statusCode, code, message)
+}
+
+// localize returns the message for code in the language requested by
+// Accept-Language, then the given message, then the English catalog entry
+func localize(c *gin.Context, code, message string) string {
+	if translated, ok := lookupMessage(code, acceptedLanguages(c)); ok {
+		return translated
+	}
+	if message != "" {
+		return message
+	}
+	if english, ok := lookupMessage(code, []string{"en"}); ok {
+		return english
+	}
+	return message
+}
+
+// lookupMessage returns the first registered message for code among locales
+func lookupMessage(code string, locales []string) (string, bool) {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+
+	messages := catalog[code]
+	for _, locale := range locales {
+		if message, ok := messages[locale]; ok {
+			return message, true
+		}
+	}
+	return "", false
+}
+
+// defaultLocaleName returns the configured default locale
+func defaultLocaleName() string {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	return defaultLocale
+}
+
+// acceptedLanguages parses Accept-Language into locales ordered by preference.
+// Region tags like "ko-KR" are followed by their base language "ko".
+func acceptedLanguages(c *gin.Context) []string {
+	header := c.GetHeader("Accept-Language")
+	if header == "" {
+		return nil
+	}
+
+	type language struct {
+		tag     string
+		quality float64
+	}
+	var languages []language
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		if quality > 0 {
+			languages = append(languages, language{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].quality > languages[j].quality
+	})
+
+	locales := make([]string, 0, len(languages)*2)
+	for _, lang := range languages {
+		locales = append(locales, lang.tag)
+		if base, _, found := strings.Cut(lang.tag, "-"); found {
+			locales = append(locales, base)
+		}
+	}
+	return locales
+}
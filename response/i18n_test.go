@@ -0,0 +1,132 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+// errorMessageFor sends Fail for code with the given Accept-Language and returns the message
+func errorMessageFor(t *testing.T, code, acceptLanguage string) string {
+	t.Helper()
+	c, w := newTestContext()
+	if acceptLanguage != "" {
+		c.Request.Header.Set("Accept-Language", acceptLanguage)
+	}
+	Fail(c, http.StatusNotFound, code)
+
+	var body ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	return body.Error.Message
+}
+
+func TestFailPicksMessageByAcceptLanguage(t *testing.T) {
+	RegisterMessage("I18N_WORKSPACE_NOT_FOUND", "en", "Workspace not found")
+	RegisterMessage("I18N_WORKSPACE_NOT_FOUND", "ko", "워크스페이스를 찾을 수 없습니다")
+
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"ko", "워크스페이스를 찾을 수 없습니다"},
+		{"ko-KR,ko;q=0.9,en;q=0.8", "워크스페이스를 찾을 수 없습니다"},
+		{"en-US", "Workspace not found"},
+		{"fr;q=1, ko;q=0.5, en;q=0.7", "Workspace not found"},
+		{"fr", "Workspace not found"},
+		{"", "Workspace not found"},
+	}
+	for _, tt := range tests {
+		if got := errorMessageFor(t, "I18N_WORKSPACE_NOT_FOUND", tt.acceptLanguage); got != tt.want {
+			t.Errorf("Accept-Language %q: message = %q, want %q", tt.acceptLanguage, got, tt.want)
+		}
+	}
+}
+
+func TestFailUnregisteredCodeUsesStatusText(t *testing.T) {
+	if got := errorMessageFor(t, "I18N_UNREGISTERED", "ko"); got != http.StatusText(http.StatusNotFound) {
+		t.Errorf("message = %q, want status text", got)
+	}
+}
+
+func TestErrorTranslatesRegisteredCode(t *testing.T) {
+	RegisterMessages("ko", map[string]string{"I18N_QUOTA": "할당량을 초과했습니다"})
+
+	c, w := newTestContext()
+	c.Request.Header.Set("Accept-Language", "ko")
+	Error(c, http.StatusTooManyRequests, "I18N_QUOTA", "Quota exceeded")
+
+	var body ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body.Error.Message != "할당량을 초과했습니다" {
+		t.Errorf("message = %q, want the ko translation", body.Error.Message)
+	}
+}
+
+// errorBody sends Error with acceptLanguage and returns the decoded envelope
+func errorBody(t *testing.T, acceptLanguage, code, message string) ErrorResponse {
+	t.Helper()
+	c, w := newTestContext()
+	if acceptLanguage != "" {
+		c.Request.Header.Set("Accept-Language", acceptLanguage)
+	}
+	Error(c, http.StatusConflict, code, message)
+
+	var body ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	return body
+}
+
+func TestErrorLocalizationFallback(t *testing.T) {
+	RegisterMessages("ko", map[string]string{"I18N_DUPLICATE": "이미 존재하는 항목입니다"})
+	RegisterMessages("en", map[string]string{"I18N_DUPLICATE": "Item already exists", "I18N_ENGLISH_ONLY": "English only"})
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		code           string
+		message        string
+		want           string
+	}{
+		{"ko translation", "ko", "I18N_DUPLICATE", "duplicate", "이미 존재하는 항목입니다"},
+		{"en translation", "en", "I18N_DUPLICATE", "duplicate", "Item already exists"},
+		{"unknown language uses the passed message", "ja", "I18N_DUPLICATE", "duplicate", "duplicate"},
+		{"no passed message falls back to English", "ja", "I18N_ENGLISH_ONLY", "", "English only"},
+		{"unregistered code keeps the passed message", "ko", "I18N_UNKNOWN_CODE", "as given", "as given"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := errorBody(t, tt.acceptLanguage, tt.code, tt.message)
+			if body.Error.Message != tt.want {
+				t.Errorf("message = %q, want %q", body.Error.Message, tt.want)
+			}
+			if body.Error.Code != tt.code || body.RequestID != "req-1" {
+				t.Errorf("code %q, requestId %q, want them untranslated", body.Error.Code, body.RequestID)
+			}
+		})
+	}
+}
+
+func TestErrorFromCodeTranslates(t *testing.T) {
+	RegisterErrorCode("I18N_LOCKED", http.StatusLocked, "Resource is locked")
+	RegisterMessage("I18N_LOCKED", "ko", "리소스가 잠겨 있습니다")
+
+	for acceptLanguage, want := range map[string]string{"ko": "리소스가 잠겨 있습니다", "de": "Resource is locked"} {
+		c, w := newTestContext()
+		c.Request.Header.Set("Accept-Language", acceptLanguage)
+		ErrorFromCode(c, "I18N_LOCKED")
+
+		var body ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if w.Code != http.StatusLocked || body.Error.Message != want {
+			t.Errorf("Accept-Language %s: got %d %q, want 423 %q", acceptLanguage, w.Code, body.Error.Message, want)
+		}
+	}
+}
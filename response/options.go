@@ -0,0 +1,34 @@
+package response
+
+import "sync/atomic"
+
+// Options customizes the success envelope for consumers that expect a different shape
+type Options struct {
+	// DataKey is the envelope key holding the payload of success and paginated
+	// responses, including streamed ones, "data" when empty
+	DataKey string
+
+	// DisableEnvelope makes Success, OK and Created write the payload as the
+	// whole body and send the request ID in the X-Request-ID header instead.
+	// Paginated and error responses keep their envelope.
+	DisableEnvelope bool
+}
+
+var options atomic.Pointer[Options]
+
+// Configure sets the envelope options for all responses. Call it once at startup.
+func Configure(opts Options) {
+	options.Store(&opts)
+}
+
+// currentOptions returns the configured options with defaults applied
+func currentOptions() Options {
+	opts := Options{}
+	if configured := options.Load(); configured != nil {
+		opts = *configured
+	}
+	if opts.DataKey == "" {
+		opts.DataKey = "data"
+	}
+	return opts
+}
@@ -0,0 +1,96 @@
+package response
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the RFC 7807 media type
+const ProblemContentType = "application/problem+json"
+
+// ValidationProblemType is the problem type URI used by ValidationProblem.
+// Services may point it at their own documentation.
+var ValidationProblemType = "/problems/validation-error"
+
+// ProblemDetails represents an RFC 7807 problem details body
+type ProblemDetails struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// ValidationProblemDetails is a problem details body with field-level errors
+type ValidationProblemDetails struct {
+	ProblemDetails
+	Errors map[string]string `json:"errors"`
+}
+
+// Problem sends an RFC 7807 application/problem+json response. An empty
+// problemType defaults to "about:blank" and an empty title to the status text.
+func Problem(c *gin.Context, status int, problemType, title, detail string) {
+	writeProblem(c, status, newProblem(c, status, problemType, title, detail))
+}
+
+// ProblemWithExtensions sends a problem response with extra members such as
+// validation specifics. Extensions cannot replace the standard members.
+func ProblemWithExtensions(c *gin.Context, status int, problemType, title, detail string, extensions map[string]interface{}) {
+	problem := newProblem(c, status, problemType, title, detail)
+
+	body := make(map[string]interface{}, len(extensions)+6)
+	for key, value := range extensions {
+		body[key] = value
+	}
+	body["type"] = problem.Type
+	body["title"] = problem.Title
+	body["status"] = problem.Status
+	if problem.Detail != "" {
+		body["detail"] = problem.Detail
+	}
+	if problem.Instance != "" {
+		body["instance"] = problem.Instance
+	}
+	body[requestIDField()] = problem.RequestID
+
+	writeProblem(c, status, body)
+}
+
+// ValidationProblem sends a 400 application/problem+json response whose
+// "errors" member maps field names to messages
+func ValidationProblem(c *gin.Context, errors map[string]string) {
+	if errors == nil {
+		errors = map[string]string{}
+	}
+	writeProblem(c, http.StatusBadRequest, ValidationProblemDetails{
+		ProblemDetails: newProblem(c, http.StatusBadRequest, ValidationProblemType, "Validation failed", ""),
+		Errors:         errors,
+	})
+}
+
+// newProblem fills in the standard members for the current request
+func newProblem(c *gin.Context, status int, problemType, title, detail string) ProblemDetails {
+	if problemType == "" {
+		problemType = "about:blank"
+	}
+	if title == "" {
+		title = http.StatusText(status)
+	}
+	return ProblemDetails{
+		Type:      problemType,
+		Title:     title,
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.Request.URL.Path,
+		RequestID: getRequestID(c),
+	}
+}
+
+// writeProblem writes obj with the problem+json content type
+func writeProblem(c *gin.Context, statusCode int, obj interface{}) {
+	// gin keeps a Content-Type that is already set
+	c.Header("Content-Type", ProblemContentType)
+	writeJSON(c, statusCode, obj)
+}
@@ -0,0 +1,75 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestValidationProblem(t *testing.T) {
+	c, w := newTestContext()
+	ValidationProblem(c, map[string]string{"email": "must be a valid email", "name": "required"})
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, ProblemContentType)
+	}
+
+	var body ValidationProblemDetails
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body.Type != ValidationProblemType || body.Title != "Validation failed" || body.Status != http.StatusBadRequest {
+		t.Errorf("problem = %+v, want the validation type, title and status", body.ProblemDetails)
+	}
+	if body.Instance != "/" || body.RequestID != "req-1" {
+		t.Errorf("instance %q, requestId %q, want / and req-1", body.Instance, body.RequestID)
+	}
+	if len(body.Errors) != 2 || body.Errors["email"] != "must be a valid email" || body.Errors["name"] != "required" {
+		t.Errorf("errors = %v, want both field errors", body.Errors)
+	}
+}
+
+func TestValidationProblemNilErrorsIsEmptyObject(t *testing.T) {
+	c, w := newTestContext()
+	ValidationProblem(c, nil)
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if string(body["errors"]) != "{}" {
+		t.Errorf("errors = %s, want {}", body["errors"])
+	}
+}
+
+func TestProblemDefaults(t *testing.T) {
+	c, w := newTestContext()
+	Problem(c, http.StatusNotFound, "", "", "")
+
+	var body ProblemDetails
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body.Type != "about:blank" || body.Title != "Not Found" || body.Status != http.StatusNotFound {
+		t.Errorf("problem = %+v, want about:blank with the status text", body)
+	}
+}
+
+func TestProblemWithExtensionsKeepsStandardMembers(t *testing.T) {
+	c, w := newTestContext()
+	ProblemWithExtensions(c, http.StatusConflict, "/problems/conflict", "Conflict", "already exists", map[string]interface{}{
+		"status":   999,
+		"resource": "workspace",
+	})
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body["status"] != float64(http.StatusConflict) || body["resource"] != "workspace" || body["detail"] != "already exists" {
+		t.Errorf("body = %v, want the extension and the standard status", body)
+	}
+}
@@ -2,7 +2,12 @@
 package response
 
 import (
+	"math"
 	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -11,6 +16,7 @@ import (
 // SuccessResponse represents a successful API response
 type SuccessResponse struct {
 	Data      interface{} `json:"data"`
+	Warnings  []string    `json:"warnings,omitempty"`
 	RequestID string      `json:"requestId"`
 }
 
@@ -42,6 +48,34 @@ type PaginationMeta struct {
 	TotalPages int   `json:"totalPages"`
 }
 
+// CursorPaginatedResponse represents a cursor-paginated response
+type CursorPaginatedResponse struct {
+	Data       interface{}          `json:"data"`
+	Pagination CursorPaginationMeta `json:"pagination"`
+	RequestID  string               `json:"requestId"`
+}
+
+// CursorPaginationMeta contains cursor pagination metadata. Cursors are
+// opaque to clients and empty when there is no page in that direction.
+type CursorPaginationMeta struct {
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+	HasMore    bool   `json:"hasMore"`
+}
+
+// PageRequest holds the pagination and sort parameters of a list request
+type PageRequest struct {
+	Page    int
+	PerPage int
+	Sort    string // sort field, empty for none
+	Desc    bool
+}
+
+// Offset returns the number of rows to skip for the requested page
+func (p PageRequest) Offset() int {
+	return (p.Page - 1) * p.PerPage
+}
+
 // getRequestID gets or generates a request ID from context
 func getRequestID(c *gin.Context) string {
 	// Try to get request ID from context (if set by middleware)
@@ -54,9 +88,23 @@ func getRequestID(c *gin.Context) string {
 	return uuid.New().String()
 }
 
+// writeJSON renders obj as indented JSON in debug mode and compact JSON otherwise
+func writeJSON(c *gin.Context, statusCode int, obj interface{}) {
+	if gin.IsDebugging() {
+		c.IndentedJSON(statusCode, obj)
+		return
+	}
+	c.JSON(statusCode, obj)
+}
+
 // Success sends a successful response with the given data
 func Success(c *gin.Context, statusCode int, data interface{}) {
-	c.JSON(statusCode, SuccessResponse{
+	if currentOptions().DisableEnvelope {
+		c.Header("X-Request-ID", getRequestID(c))
+		writeJSON(c, statusCode, data)
+		return
+	}
+	writeJSON(c, statusCode, SuccessResponse{
 		Data:      data,
 		RequestID: getRequestID(c),
 	})
@@ -67,22 +115,61 @@ func OK(c *gin.Context, data interface{}) {
 	Success(c, http.StatusOK, data)
 }
 
+// OKWithWarnings sends a 200 OK response for an operation that succeeded with
+// non-fatal problems, listed in a warnings field that is omitted when empty.
+// Warnings are not sent when the envelope is disabled.
+func OKWithWarnings(c *gin.Context, data interface{}, warnings []string) {
+	if currentOptions().DisableEnvelope || len(warnings) == 0 {
+		OK(c, data)
+		return
+	}
+	writeJSON(c, http.StatusOK, SuccessResponse{
+		Data:      data,
+		Warnings:  warnings,
+		RequestID: getRequestID(c),
+	})
+}
+
+// OKList sends a 200 OK response for a list, encoding a nil slice as [] rather than null
+func OKList(c *gin.Context, slice interface{}) {
+	OK(c, emptyIfNil(slice))
+}
+
+// emptyIfNil replaces a nil slice (or untyped nil) with an empty one
+func emptyIfNil(slice interface{}) interface{} {
+	if slice == nil {
+		return []interface{}{}
+	}
+	v := reflect.ValueOf(slice)
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		return reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+	return slice
+}
+
 // Created sends a 201 Created response
 func Created(c *gin.Context, data interface{}) {
 	Success(c, http.StatusCreated, data)
 }
 
+// CreatedAt sends a 201 Created response with the Location header set to the new resource URL
+func CreatedAt(c *gin.Context, location string, data interface{}) {
+	c.Header("Location", location)
+	Created(c, data)
+}
+
 // NoContent sends a 204 No Content response
 func NoContent(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
-// Error sends an error response
+// Error sends an error response. The message is replaced by the translation
+// registered for code in the Accept-Language language, if any.
 func Error(c *gin.Context, statusCode int, code string, message string) {
-	c.JSON(statusCode, ErrorResponse{
+	writeJSON(c, statusCode, ErrorResponse{
 		Error: ErrorDetail{
 			Code:    code,
-			Message: message,
+			Message: localize(c, code, message),
 		},
 		RequestID: getRequestID(c),
 	})
@@ -90,10 +177,10 @@ func Error(c *gin.Context, statusCode int, code string, message string) {
 
 // ErrorWithDetails sends an error response with additional details
 func ErrorWithDetails(c *gin.Context, statusCode int, code string, message string, details interface{}) {
-	c.JSON(statusCode, ErrorResponse{
+	writeJSON(c, statusCode, ErrorResponse{
 		Error: ErrorDetail{
 			Code:    code,
-			Message: message,
+			Message: localize(c, code, message),
 			Details: details,
 		},
 		RequestID: getRequestID(c),
@@ -102,48 +189,156 @@ func ErrorWithDetails(c *gin.Context, statusCode int, code string, message strin
 
 // BadRequest sends a 400 Bad Request error
 func BadRequest(c *gin.Context, message string) {
-	Error(c, http.StatusBadRequest, "BAD_REQUEST", message)
+	Error(c, http.StatusBadRequest, string(CodeBadRequest), message)
 }
 
 // Unauthorized sends a 401 Unauthorized error
 func Unauthorized(c *gin.Context, message string) {
-	Error(c, http.StatusUnauthorized, "UNAUTHORIZED", message)
+	Error(c, http.StatusUnauthorized, string(CodeUnauthorized), message)
 }
 
 // Forbidden sends a 403 Forbidden error
 func Forbidden(c *gin.Context, message string) {
-	Error(c, http.StatusForbidden, "FORBIDDEN", message)
+	Error(c, http.StatusForbidden, string(CodeForbidden), message)
 }
 
 // NotFound sends a 404 Not Found error
 func NotFound(c *gin.Context, message string) {
-	Error(c, http.StatusNotFound, "NOT_FOUND", message)
+	Error(c, http.StatusNotFound, string(CodeNotFound), message)
+}
+
+// MethodNotAllowed sends a 405 Method Not Allowed error with an Allow header listing the valid methods
+func MethodNotAllowed(c *gin.Context, allowed []string) {
+	c.Header("Allow", strings.Join(allowed, ", "))
+	Error(c, http.StatusMethodNotAllowed, string(CodeMethodNotAllowed), "Method not allowed")
+}
+
+// NoMethodHandler returns a gin NoMethod handler that responds with MethodNotAllowed.
+// Install it with router.HandleMethodNotAllowed = true and router.NoMethod(response.NoMethodHandler()).
+func NoMethodHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// gin sets Allow from the registered routes before calling NoMethod handlers
+		var allowed []string
+		for _, method := range strings.Split(c.Writer.Header().Get("Allow"), ",") {
+			if method = strings.TrimSpace(method); method != "" {
+				allowed = append(allowed, method)
+			}
+		}
+		MethodNotAllowed(c, allowed)
+	}
 }
 
 // Conflict sends a 409 Conflict error
 func Conflict(c *gin.Context, message string) {
-	Error(c, http.StatusConflict, "CONFLICT", message)
+	Error(c, http.StatusConflict, string(CodeConflict), message)
+}
+
+// Gone sends a 410 Gone error
+func Gone(c *gin.Context, message string) {
+	Error(c, http.StatusGone, string(CodeGone), message)
+}
+
+// PreconditionFailed sends a 412 Precondition Failed error, e.g. when If-Match does not match
+func PreconditionFailed(c *gin.Context, message string) {
+	Error(c, http.StatusPreconditionFailed, string(CodePreconditionFailed), message)
+}
+
+// PreconditionRequired sends a 428 Precondition Required error
+func PreconditionRequired(c *gin.Context, message string) {
+	Error(c, http.StatusPreconditionRequired, string(CodePreconditionRequired), message)
+}
+
+// UnprocessableEntity sends a 422 Unprocessable Entity error
+func UnprocessableEntity(c *gin.Context, message string) {
+	Error(c, http.StatusUnprocessableEntity, string(CodeUnprocessableEntity), message)
+}
+
+// TooManyRequests sends a 429 Too Many Requests error
+func TooManyRequests(c *gin.Context, message string) {
+	Error(c, http.StatusTooManyRequests, string(CodeTooManyRequests), message)
+}
+
+// TooManyRequestsWithRetry sends a 429 Too Many Requests error with a
+// Retry-After header, rounded up to whole seconds
+func TooManyRequestsWithRetry(c *gin.Context, message string, retryAfter time.Duration) {
+	if retryAfter > 0 {
+		c.Header("Retry-After", strconv.FormatInt(int64(math.Ceil(retryAfter.Seconds())), 10))
+	}
+	TooManyRequests(c, message)
 }
 
 // InternalError sends a 500 Internal Server Error
 func InternalError(c *gin.Context, message string) {
-	Error(c, http.StatusInternalServerError, "INTERNAL_ERROR", message)
+	Error(c, http.StatusInternalServerError, string(CodeInternalError), message)
+}
+
+// NotImplemented sends a 501 Not Implemented error
+func NotImplemented(c *gin.Context, message string) {
+	Error(c, http.StatusNotImplemented, string(CodeNotImplemented), message)
+}
+
+// ServiceUnavailable sends a 503 Service Unavailable error
+func ServiceUnavailable(c *gin.Context, message string) {
+	Error(c, http.StatusServiceUnavailable, string(CodeServiceUnavailable), message)
 }
 
 // Paginated sends a paginated response
 func Paginated(c *gin.Context, data interface{}, page, perPage int, total int64) {
-	totalPages := int(total) / perPage
-	if int(total)%perPage > 0 {
-		totalPages++
-	}
-
-	c.JSON(http.StatusOK, PaginatedResponse{
+	writeJSON(c, http.StatusOK, PaginatedResponse{
 		Data: data,
 		Pagination: PaginationMeta{
 			Page:       page,
 			PerPage:    perPage,
 			Total:      total,
-			TotalPages: totalPages,
+			TotalPages: totalPages(total, perPage),
+		},
+		RequestID: getRequestID(c),
+	})
+}
+
+// totalPages returns the number of pages needed for total items. A perPage of
+// 0 or less is treated as everything on a single page. The math is done in
+// int64, and the result capped at the largest int, so large totals don't
+// overflow on 32-bit builds.
+func totalPages(total int64, perPage int) int {
+	if total <= 0 {
+		return 0
+	}
+	if perPage <= 0 {
+		return 1
+	}
+	pages := total / int64(perPage)
+	if total%int64(perPage) != 0 {
+		pages++
+	}
+	if pages > math.MaxInt {
+		return math.MaxInt
+	}
+	return int(pages)
+}
+
+// PaginatedFromRequest sends a paginated response using the page parameters
+// parsed into the context by middleware.PaginationParams
+func PaginatedFromRequest(c *gin.Context, data interface{}, total int64) {
+	// Without parsed parameters everything is treated as a single page
+	params := PageRequest{Page: 1, PerPage: int(total)}
+	if value, exists := c.Get("pagination"); exists {
+		if p, ok := value.(PageRequest); ok {
+			params = p
+		}
+	}
+	Paginated(c, data, params.Page, params.PerPage, total)
+}
+
+// CursorPaginated sends a cursor-paginated response, which stays stable on
+// tables that change between page requests unlike offset pagination
+func CursorPaginated(c *gin.Context, data interface{}, nextCursor, prevCursor string, hasMore bool) {
+	writeJSON(c, http.StatusOK, CursorPaginatedResponse{
+		Data: data,
+		Pagination: CursorPaginationMeta{
+			NextCursor: nextCursor,
+			PrevCursor: prevCursor,
+			HasMore:    hasMore,
 		},
 		RequestID: getRequestID(c),
 	})
@@ -151,5 +346,5 @@ func Paginated(c *gin.Context, data interface{}, page, perPage int, total int64)
 
 // ValidationError sends a validation error with field details
 func ValidationError(c *gin.Context, errors map[string]string) {
-	ErrorWithDetails(c, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", errors)
+	ErrorWithDetails(c, http.StatusBadRequest, string(CodeValidationError), "Validation failed", errors)
 }
@@ -0,0 +1,277 @@
+package response
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTestContext returns a gin context for a GET / request with request_id set
+func newTestContext() (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Set("request_id", "req-1")
+	return c, w
+}
+
+// withMode runs fn with gin in mode, restoring release mode afterwards
+func withMode(t *testing.T, mode string, fn func()) {
+	t.Helper()
+	gin.SetMode(mode)
+	defer gin.SetMode(gin.ReleaseMode)
+	fn()
+}
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.ReleaseMode)
+	m.Run()
+}
+
+func TestSuccessIndentedInDebugMode(t *testing.T) {
+	withMode(t, gin.DebugMode, func() {
+		c, w := newTestContext()
+		OK(c, map[string]string{"name": "alice"})
+
+		if !strings.Contains(w.Body.String(), "\n    ") {
+			t.Fatalf("expected indented JSON in debug mode, got %q", w.Body.String())
+		}
+	})
+}
+
+func TestSuccessCompactInReleaseMode(t *testing.T) {
+	withMode(t, gin.ReleaseMode, func() {
+		c, w := newTestContext()
+		OK(c, map[string]string{"name": "alice"})
+
+		want := `{"data":{"name":"alice"},"requestId":"req-1"}`
+		if got := w.Body.String(); got != want {
+			t.Fatalf("body = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestErrorIndentedInDebugMode(t *testing.T) {
+	withMode(t, gin.DebugMode, func() {
+		c, w := newTestContext()
+		NotFound(c, "missing")
+
+		if !strings.Contains(w.Body.String(), "\n    ") {
+			t.Fatalf("expected indented JSON in debug mode, got %q", w.Body.String())
+		}
+		var body ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if body.Error.Code != string(CodeNotFound) {
+			t.Fatalf("code = %q, want %q", body.Error.Code, CodeNotFound)
+		}
+	})
+}
+
+func TestCreatedAtSetsLocation(t *testing.T) {
+	c, w := newTestContext()
+	CreatedAt(c, "/api/v1/users/42", map[string]int{"id": 42})
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusCreated)
+	}
+	if got := w.Header().Get("Location"); got != "/api/v1/users/42" {
+		t.Errorf("Location = %q, want %q", got, "/api/v1/users/42")
+	}
+	want := `{"data":{"id":42},"requestId":"req-1"}`
+	if got := w.Body.String(); got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
+
+func TestCreatedHasNoLocation(t *testing.T) {
+	c, w := newTestContext()
+	Created(c, nil)
+
+	if w.Code != http.StatusCreated || w.Header().Get("Location") != "" {
+		t.Fatalf("got %d with Location %q, want 201 without Location", w.Code, w.Header().Get("Location"))
+	}
+}
+
+func TestOKListNilSliceIsEmptyArray(t *testing.T) {
+	var users []string
+	for _, data := range []interface{}{users, nil} {
+		c, w := newTestContext()
+		OKList(c, data)
+
+		want := `{"data":[],"requestId":"req-1"}`
+		if got := w.Body.String(); got != want {
+			t.Errorf("OKList(%#v) body = %q, want %q", data, got, want)
+		}
+	}
+}
+
+func TestOKListPopulatedSlice(t *testing.T) {
+	c, w := newTestContext()
+	OKList(c, []string{"alice", "bob"})
+
+	want := `{"data":["alice","bob"],"requestId":"req-1"}`
+	if got := w.Body.String(); got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
+
+func TestOKWithWarnings(t *testing.T) {
+	c, w := newTestContext()
+	OKWithWarnings(c, map[string]int{"imported": 8}, []string{"row 3: missing email", "row 7: duplicate"})
+
+	want := `{"data":{"imported":8},"warnings":["row 3: missing email","row 7: duplicate"],"requestId":"req-1"}`
+	if w.Code != http.StatusOK || w.Body.String() != want {
+		t.Errorf("status %d, body = %q, want 200 with %q", w.Code, w.Body.String(), want)
+	}
+}
+
+func TestOKWithWarningsOmitsEmpty(t *testing.T) {
+	for _, warnings := range [][]string{nil, {}} {
+		c, w := newTestContext()
+		OKWithWarnings(c, "done", warnings)
+
+		want := `{"data":"done","requestId":"req-1"}`
+		if got := w.Body.String(); got != want {
+			t.Errorf("warnings %#v: body = %q, want %q", warnings, got, want)
+		}
+	}
+}
+
+func TestOKWithWarningsEnvelopeOptions(t *testing.T) {
+	defer Configure(Options{})
+
+	Configure(Options{DataKey: "result"})
+	c, w := newTestContext()
+	OKWithWarnings(c, "done", []string{"skipped"})
+	if want := `{"result":"done","warnings":["skipped"],"requestId":"req-1"}`; w.Body.String() != want {
+		t.Errorf("custom data key: body = %q, want %q", w.Body.String(), want)
+	}
+
+	Configure(Options{DisableEnvelope: true})
+	c, w = newTestContext()
+	OKWithWarnings(c, "done", []string{"skipped"})
+	if want := `"done"`; w.Body.String() != want {
+		t.Errorf("envelope disabled: body = %q, want %q", w.Body.String(), want)
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	c, w := newTestContext()
+	MethodNotAllowed(c, []string{"GET", "POST"})
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want 405", w.Code)
+	}
+	if allow := w.Header().Get("Allow"); allow != "GET, POST" {
+		t.Errorf("Allow = %q, want %q", allow, "GET, POST")
+	}
+	var body ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body.Error.Code != string(CodeMethodNotAllowed) {
+		t.Errorf("code = %q, want %q", body.Error.Code, CodeMethodNotAllowed)
+	}
+}
+
+func TestNoMethodHandler(t *testing.T) {
+	router := gin.New()
+	router.HandleMethodNotAllowed = true
+	router.NoMethod(NoMethodHandler())
+	router.GET("/items", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/items", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/items", nil))
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want 405", w.Code)
+	}
+	allow := w.Header().Get("Allow")
+	if !strings.Contains(allow, "GET") || !strings.Contains(allow, "POST") {
+		t.Errorf("Allow = %q, want GET and POST", allow)
+	}
+	var body ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body.Error.Code != string(CodeMethodNotAllowed) {
+		t.Errorf("code = %q, want %q", body.Error.Code, CodeMethodNotAllowed)
+	}
+}
+
+func TestTotalPages(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int64
+		perPage int
+		want    int
+	}{
+		{"exact multiple", 40, 20, 2},
+		{"partial last page", 41, 20, 3},
+		{"no items", 0, 20, 0},
+		{"zero perPage", 41, 0, 1},
+		{"zero perPage and no items", 0, 0, 0},
+		{"negative perPage", 41, -5, 1},
+		{"total beyond 2^31", 1<<31 + 10, 10, 214748366},
+		{"total beyond 2^31 on one page each", 1 << 32, 1, int(min(int64(1)<<32, int64(math.MaxInt)))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := totalPages(tt.total, tt.perPage); got != tt.want {
+				t.Errorf("totalPages(%d, %d) = %d, want %d", tt.total, tt.perPage, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginatedZeroPerPageDoesNotPanic(t *testing.T) {
+	c, w := newTestContext()
+	Paginated(c, []int{1, 2, 3}, 1, 0, 3)
+
+	var body PaginatedResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body.Pagination.TotalPages != 1 || body.Pagination.Total != 3 {
+		t.Errorf("pagination = %+v, want a single page of 3", body.Pagination)
+	}
+}
+
+func TestDataKeyAppliesToPaginatedResponses(t *testing.T) {
+	defer Configure(Options{})
+	Configure(Options{DataKey: "result"})
+
+	tests := []struct {
+		name string
+		send func(c *gin.Context)
+		want string
+	}{
+		{"Paginated", func(c *gin.Context) { Paginated(c, []int{1}, 1, 10, 1) },
+			`{"result":[1],"pagination":{"page":1,"perPage":10,"total":1,"totalPages":1},"requestId":"req-1"}`},
+		{"CursorPaginated", func(c *gin.Context) { CursorPaginated(c, []int{1}, "next", "", true) },
+			`{"result":[1],"pagination":{"nextCursor":"next","hasMore":true},"requestId":"req-1"}`},
+		{"PaginatedStream", func(c *gin.Context) {
+			items := make(chan interface{}, 1)
+			items <- 1
+			close(items)
+			PaginatedStream(c, PaginationMeta{Page: 1, PerPage: 10, Total: 1, TotalPages: 1}, items)
+		}, `{"result":[1],"pagination":{"page":1,"perPage":10,"total":1,"totalPages":1},"requestId":"req-1"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := newTestContext()
+			tt.send(c)
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamFlushEvery is the number of items written between flushes
+const streamFlushEvery = 100
+
+// PaginatedStream streams items from the channel as the data array of a
+// paginated envelope, for lists too large to hold in memory. Pagination is sent
+// in headers up front and repeated after the array. Streaming stops when the
+// client disconnects, so producers should also watch the request context.
+func PaginatedStream(c *gin.Context, meta PaginationMeta, items <-chan interface{}) {
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Header("X-Total-Count", strconv.FormatInt(meta.Total, 10))
+	c.Header("X-Page", strconv.Itoa(meta.Page))
+	c.Header("X-Per-Page", strconv.Itoa(meta.PerPage))
+	c.Header("X-Total-Pages", strconv.Itoa(meta.TotalPages))
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	done := c.Request.Context().Done()
+	dataKey, _ := json.Marshal(currentOptions().DataKey)
+	_, _ = w.WriteString("{" + string(dataKey) + ":[")
+
+	count := 0
+stream:
+	for {
+		select {
+		case <-done:
+			return
+		case item, ok := <-items:
+			if !ok {
+				break stream
+			}
+			encoded, err := json.Marshal(item)
+			if err != nil {
+				_ = c.Error(err)
+				continue
+			}
+			if count > 0 {
+				_, _ = w.WriteString(",")
+			}
+			_, _ = w.Write(encoded)
+			count++
+			if count%streamFlushEvery == 0 {
+				w.Flush()
+			}
+		}
+	}
+
+	pagination, _ := json.Marshal(meta)
+	requestID, _ := json.Marshal(getRequestID(c))
+	_, _ = w.WriteString(`],"pagination":` + string(pagination) + `,"` + requestIDField() + `":` + string(requestID) + `}`)
+	w.Flush()
+}
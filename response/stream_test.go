@@ -0,0 +1,74 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPaginatedStreamValidJSONInOrder(t *testing.T) {
+	c, w := newTestContext()
+	meta := PaginationMeta{Page: 1, PerPage: 250, Total: 250, TotalPages: 1}
+
+	items := make(chan interface{})
+	go func() {
+		defer close(items)
+		for i := 0; i < 250; i++ {
+			items <- map[string]int{"id": i}
+		}
+	}()
+	PaginatedStream(c, meta, items)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if got := w.Header().Get("X-Total-Count"); got != "250" {
+		t.Errorf("X-Total-Count = %q, want 250", got)
+	}
+
+	var body struct {
+		Data       []map[string]int `json:"data"`
+		Pagination PaginationMeta   `json:"pagination"`
+		RequestID  string           `json:"requestId"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("streamed body is not valid JSON: %v", err)
+	}
+	if len(body.Data) != 250 {
+		t.Fatalf("got %d items, want 250", len(body.Data))
+	}
+	for i, item := range body.Data {
+		if item["id"] != i {
+			t.Fatalf("item %d has id %d, items out of order", i, item["id"])
+		}
+	}
+	if body.Pagination != meta {
+		t.Errorf("pagination = %+v, want %+v", body.Pagination, meta)
+	}
+	if body.RequestID != "req-1" {
+		t.Errorf("requestId = %q, want req-1", body.RequestID)
+	}
+}
+
+func TestPaginatedStreamEmpty(t *testing.T) {
+	c, w := newTestContext()
+	items := make(chan interface{})
+	close(items)
+	PaginatedStream(c, PaginationMeta{Page: 1, PerPage: 10}, items)
+
+	if !strings.HasPrefix(w.Body.String(), `{"data":[]`) || !json.Valid(w.Body.Bytes()) {
+		t.Errorf("body = %q, want valid JSON with an empty data array", w.Body.String())
+	}
+}
+
+func TestPaginatedStreamStopsOnDisconnect(t *testing.T) {
+	c, _ := newTestContext()
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	c.Request = c.Request.WithContext(ctx)
+	cancel()
+
+	// Nothing is ever sent or closed; PaginatedStream must return on its own
+	PaginatedStream(c, PaginationMeta{}, make(chan interface{}))
+}
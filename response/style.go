@@ -0,0 +1,173 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync/atomic"
+)
+
+// FieldStyle selects how multi-word envelope field names are spelled
+type FieldStyle int32
+
+const (
+	FieldStyleCamel FieldStyle = iota // requestId, perPage, totalPages (default)
+	FieldStyleSnake                   // request_id, per_page, total_pages
+)
+
+var fieldStyle atomic.Int32
+
+// SetFieldStyle sets the naming of envelope fields for all responses. Only the
+// envelope is affected; response data is encoded with its own json tags.
+// Call it once at startup.
+func SetFieldStyle(style FieldStyle) {
+	fieldStyle.Store(int32(style))
+}
+
+// snakeCase reports whether envelope fields use snake_case
+func snakeCase() bool {
+	return FieldStyle(fieldStyle.Load()) == FieldStyleSnake
+}
+
+// requestIDField returns the envelope key for the request ID
+func requestIDField() string {
+	if snakeCase() {
+		return "request_id"
+	}
+	return "requestId"
+}
+
+// envelopeField is a key and value written between the data and the request ID
+type envelopeField struct {
+	key   string
+	value interface{}
+}
+
+// marshalEnvelope encodes data under the configured data key, then fields in
+// order, then the request ID under the key of the configured field style
+func marshalEnvelope(data interface{}, requestID string, fields ...envelopeField) ([]byte, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, _ := json.Marshal(currentOptions().DataKey)
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	buf.Write(dataKey)
+	buf.WriteByte(':')
+	buf.Write(encoded)
+	for _, field := range fields {
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		key, _ := json.Marshal(field.key)
+		buf.WriteByte(',')
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	requestIDKey, _ := json.Marshal(requestIDField())
+	encodedID, _ := json.Marshal(requestID)
+	buf.WriteByte(',')
+	buf.Write(requestIDKey)
+	buf.WriteByte(':')
+	buf.Write(encodedID)
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalJSON encodes the response in the configured field style and with the
+// configured data key
+func (r SuccessResponse) MarshalJSON() ([]byte, error) {
+	if len(r.Warnings) > 0 {
+		return marshalEnvelope(r.Data, r.RequestID, envelopeField{"warnings", r.Warnings})
+	}
+	return marshalEnvelope(r.Data, r.RequestID)
+}
+
+// MarshalJSON encodes the response in the configured field style
+func (r ErrorResponse) MarshalJSON() ([]byte, error) {
+	if snakeCase() {
+		return json.Marshal(struct {
+			Error     ErrorDetail `json:"error"`
+			RequestID string      `json:"request_id"`
+		}{r.Error, r.RequestID})
+	}
+	type plain ErrorResponse
+	return json.Marshal(plain(r))
+}
+
+// MarshalJSON encodes the response in the configured field style and with the
+// configured data key
+func (r PaginatedResponse) MarshalJSON() ([]byte, error) {
+	return marshalEnvelope(r.Data, r.RequestID, envelopeField{"pagination", r.Pagination})
+}
+
+// MarshalJSON encodes the metadata in the configured field style
+func (m PaginationMeta) MarshalJSON() ([]byte, error) {
+	if snakeCase() {
+		return json.Marshal(struct {
+			Page       int   `json:"page"`
+			PerPage    int   `json:"per_page"`
+			Total      int64 `json:"total"`
+			TotalPages int   `json:"total_pages"`
+		}{m.Page, m.PerPage, m.Total, m.TotalPages})
+	}
+	type plain PaginationMeta
+	return json.Marshal(plain(m))
+}
+
+// MarshalJSON encodes the response in the configured field style and with the
+// configured data key
+func (r CursorPaginatedResponse) MarshalJSON() ([]byte, error) {
+	return marshalEnvelope(r.Data, r.RequestID, envelopeField{"pagination", r.Pagination})
+}
+
+// MarshalJSON encodes the metadata in the configured field style
+func (m CursorPaginationMeta) MarshalJSON() ([]byte, error) {
+	if snakeCase() {
+		return json.Marshal(struct {
+			NextCursor string `json:"next_cursor,omitempty"`
+			PrevCursor string `json:"prev_cursor,omitempty"`
+			HasMore    bool   `json:"has_more"`
+		}{m.NextCursor, m.PrevCursor, m.HasMore})
+	}
+	type plain CursorPaginationMeta
+	return json.Marshal(plain(m))
+}
+
+// MarshalJSON encodes the problem in the configured field style
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	if snakeCase() {
+		return json.Marshal(snakeProblemDetails(p))
+	}
+	type plain ProblemDetails
+	return json.Marshal(plain(p))
+}
+
+// MarshalJSON encodes the problem in the configured field style. It is needed
+// because the embedded ProblemDetails method would otherwise drop Errors.
+func (p ValidationProblemDetails) MarshalJSON() ([]byte, error) {
+	if snakeCase() {
+		return json.Marshal(struct {
+			snakeProblemDetails
+			Errors map[string]string `json:"errors"`
+		}{snakeProblemDetails(p.ProblemDetails), p.Errors})
+	}
+	type plain ProblemDetails
+	return json.Marshal(struct {
+		plain
+		Errors map[string]string `json:"errors"`
+	}{plain(p.ProblemDetails), p.Errors})
+}
+
+// snakeProblemDetails is ProblemDetails with snake_case field names
+type snakeProblemDetails struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
@@ -0,0 +1,116 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// withFieldStyle runs fn with style set, restoring camelCase afterwards
+func withFieldStyle(t *testing.T, style FieldStyle, fn func()) {
+	t.Helper()
+	SetFieldStyle(style)
+	defer SetFieldStyle(FieldStyleCamel)
+	fn()
+}
+
+// keys returns the sorted keys of the JSON object raw
+func keys(t *testing.T, raw []byte) []string {
+	t.Helper()
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		t.Fatalf("invalid JSON object %s: %v", raw, err)
+	}
+	result := make([]string, 0, len(object))
+	for key := range object {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// envelopeKeys renders send and returns the top-level keys and pagination keys
+func envelopeKeys(t *testing.T, send func(c *gin.Context)) (top, pagination []string) {
+	t.Helper()
+	c, w := newTestContext()
+	send(c)
+	top = keys(t, w.Body.Bytes())
+
+	var body struct {
+		Pagination json.RawMessage `json:"pagination"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	if body.Pagination != nil {
+		pagination = keys(t, body.Pagination)
+	}
+	return top, pagination
+}
+
+func TestFieldStyle(t *testing.T) {
+	type item struct {
+		DisplayName string `json:"displayName"`
+	}
+	sends := map[string]func(c *gin.Context){
+		"success":   func(c *gin.Context) { OK(c, item{"alice"}) },
+		"error":     func(c *gin.Context) { NotFound(c, "missing") },
+		"paginated": func(c *gin.Context) { Paginated(c, []item{{"alice"}}, 1, 20, 1) },
+		"cursor":    func(c *gin.Context) { CursorPaginated(c, []item{{"alice"}}, "next", "prev", true) },
+		"problem":   func(c *gin.Context) { Problem(c, http.StatusConflict, "", "", "") },
+		"validation": func(c *gin.Context) {
+			ValidationProblem(c, map[string]string{"name": "required"})
+		},
+	}
+	want := map[FieldStyle]map[string][2][]string{
+		FieldStyleCamel: {
+			"success":    {{"data", "requestId"}},
+			"error":      {{"error", "requestId"}},
+			"paginated":  {{"data", "pagination", "requestId"}, {"page", "perPage", "total", "totalPages"}},
+			"cursor":     {{"data", "pagination", "requestId"}, {"hasMore", "nextCursor", "prevCursor"}},
+			"problem":    {{"instance", "requestId", "status", "title", "type"}},
+			"validation": {{"errors", "instance", "requestId", "status", "title", "type"}},
+		},
+		FieldStyleSnake: {
+			"success":    {{"data", "request_id"}},
+			"error":      {{"error", "request_id"}},
+			"paginated":  {{"data", "pagination", "request_id"}, {"page", "per_page", "total", "total_pages"}},
+			"cursor":     {{"data", "pagination", "request_id"}, {"has_more", "next_cursor", "prev_cursor"}},
+			"problem":    {{"instance", "request_id", "status", "title", "type"}},
+			"validation": {{"errors", "instance", "request_id", "status", "title", "type"}},
+		},
+	}
+
+	for style, cases := range want {
+		withFieldStyle(t, style, func() {
+			for name, expected := range cases {
+				top, pagination := envelopeKeys(t, sends[name])
+				if !slices.Equal(top, expected[0]) {
+					t.Errorf("style %d %s: keys = %v, want %v", style, name, top, expected[0])
+				}
+				if !slices.Equal(pagination, expected[1]) {
+					t.Errorf("style %d %s: pagination keys = %v, want %v", style, name, pagination, expected[1])
+				}
+			}
+		})
+	}
+}
+
+func TestFieldStyleLeavesDataAlone(t *testing.T) {
+	withFieldStyle(t, FieldStyleSnake, func() {
+		c, w := newTestContext()
+		OK(c, map[string]string{"displayName": "alice"})
+
+		var body struct {
+			Data map[string]string `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if body.Data["displayName"] != "alice" {
+			t.Errorf("data = %v, want its own field names", body.Data)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/config"
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// NewRouter creates a gin engine with the standard middleware stack.
+// Request ID, recovery and access logging are always installed; tracing,
+// metrics and CORS are installed according to cfg.Middleware, and rate
+// limiting when cfg.RateLimit.Enabled is set.
+func NewRouter(cfg *config.Config, logger *zap.Logger) *gin.Engine {
+	router := gin.New()
+
+	router.Use(middleware.RequestContext())
+	router.Use(middleware.Logger(logger))
+	router.Use(middleware.Recovery(logger))
+
+	if cfg.Middleware.EnableTracing {
+		router.Use(middleware.Tracing(cfg.Tracing.ServiceName))
+	}
+	if cfg.Middleware.EnableMetrics {
+		router.Use(middleware.Metrics())
+	}
+	if cfg.Middleware.EnableCORS {
+		router.Use(middleware.CORSWithOrigins(cfg.CORS.AllowedOrigins))
+	}
+	if cfg.RateLimit.Enabled {
+		router.Use(middleware.RateLimit(middleware.RateLimitConfig{
+			RequestsPerSecond: cfg.RateLimit.RequestsPerSecond,
+			Burst:             cfg.RateLimit.Burst,
+			FailClosed:        cfg.RateLimit.FailClosed,
+			ExemptCIDRs:       cfg.RateLimit.ExemptCIDRs,
+			ExemptPaths:       cfg.RateLimit.ExemptPaths,
+			ExemptAPIKeys:     cfg.RateLimit.ExemptAPIKeys,
+			APIKeyHeader:      cfg.RateLimit.APIKeyHeader,
+		}))
+	}
+
+	return router
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/config"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// newTestRouter returns a router built from cfg with a /ping route
+func newTestRouter(cfg *config.Config) *gin.Engine {
+	gin.SetMode(gin.ReleaseMode)
+	cfg.Middleware.EnableMetrics = false
+	router := NewRouter(cfg, zap.NewNop())
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+// countLimited sends n requests to /ping and returns how many got 429
+func countLimited(router *gin.Engine, n int) int {
+	limited := 0
+	for i := 0; i < n; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		if w.Code == http.StatusTooManyRequests {
+			limited++
+		}
+	}
+	return limited
+}
+
+func TestRouterRateLimitDisabledIsNoOp(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.RateLimit.RequestsPerSecond = 1
+	cfg.RateLimit.Burst = 1
+
+	if limited := countLimited(newTestRouter(cfg), 10); limited != 0 {
+		t.Fatalf("%d requests limited with rate limiting disabled", limited)
+	}
+}
+
+func TestRouterRateLimitEnabledFromConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.RateLimit.Enabled = true
+	cfg.RateLimit.RequestsPerSecond = 1
+	cfg.RateLimit.Burst = 2
+
+	if limited := countLimited(newTestRouter(cfg), 5); limited != 3 {
+		t.Fatalf("%d of 5 requests limited, want 3 beyond the burst of 2", limited)
+	}
+}
+
+func TestRouterRateLimitEnabledFromLegacyYAMLKey(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "")
+	t.Setenv("ENABLE_RATE_LIMIT", "")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "middleware:\n  enable_rate_limit: true\nrate_limit:\n  requests_per_second: 1\n  burst: 1\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if limited := countLimited(newTestRouter(cfg), 5); limited != 4 {
+		t.Fatalf("%d of 5 requests limited with middleware.enable_rate_limit set, want 4", limited)
+	}
+}
+
+func TestRouterRateLimitAPIKeyHeader(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.RateLimit.Enabled = true
+	cfg.RateLimit.RequestsPerSecond = 1
+	cfg.RateLimit.Burst = 1
+	cfg.RateLimit.ExemptAPIKeys = []string{"internal-key"}
+	cfg.RateLimit.APIKeyHeader = "X-Service-Key"
+	router := newTestRouter(cfg)
+
+	for i := 0; i < 5; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set("X-Service-Key", "internal-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d with the exempt key in the configured header: status = %d, want 200", i, w.Code)
+		}
+	}
+}
+
+func TestRouterInstallsOnlyEnabledMiddleware(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	cfg := config.DefaultConfig()
+	cfg.Middleware = config.MiddlewareConfig{}
+
+	// RequestContext, Logger and Recovery are always installed
+	if got := len(NewRouter(cfg, zap.NewNop()).Handlers); got != 3 {
+		t.Errorf("%d handlers with everything disabled, want 3", got)
+	}
+
+	cfg.Middleware.EnableMetrics = true
+	cfg.Middleware.EnableCORS = true
+	if got := len(NewRouter(cfg, zap.NewNop()).Handlers); got != 5 {
+		t.Errorf("%d handlers with metrics and CORS enabled, want 5", got)
+	}
+}
+
+func TestRouterCORSDisabled(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		cfg := config.DefaultConfig()
+		cfg.Middleware.EnableCORS = enabled
+		router := newTestRouter(cfg)
+
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		got := w.Header().Get("Access-Control-Allow-Origin") != ""
+		if got != enabled {
+			t.Errorf("EnableCORS=%v: CORS headers sent = %v", enabled, got)
+		}
+	}
+}
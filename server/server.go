@@ -0,0 +1,73 @@
+// Package server provides HTTP server lifecycle helpers for all Go services.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/config"
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/health"
+)
+
+// New creates an http.Server for handler using the configured port and timeouts
+func New(handler http.Handler, cfg config.ServerConfig) *http.Server {
+	return &http.Server{
+		Addr:         fmt.Sprintf(":%d", cfg.Port),
+		Handler:      handler,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+	}
+}
+
+// Run starts srv and blocks until SIGINT or SIGTERM is received, then shuts it
+// down gracefully. healthHandler may be nil if the service has no readiness probe.
+func Run(srv *http.Server, cfg config.ServerConfig, healthHandler *health.Handler) error {
+	errCh := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-quit:
+	}
+
+	return Shutdown(srv, cfg, healthHandler)
+}
+
+// Shutdown drains and stops srv. Readiness is flipped to unhealthy first, then
+// PreShutdownDelay elapses so load balancers deregister the instance, and only
+// then does srv shut down, waiting up to ShutdownTimeout for in-flight requests.
+func Shutdown(srv *http.Server, cfg config.ServerConfig, healthHandler *health.Handler) error {
+	if healthHandler != nil {
+		healthHandler.Drain()
+	}
+	if cfg.PreShutdownDelay > 0 {
+		time.Sleep(cfg.PreShutdownDelay)
+	}
+
+	ctx := context.Background()
+	if cfg.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.ShutdownTimeout)
+		defer cancel()
+	}
+
+	if err := srv.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to shutdown server: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/config"
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/health"
+	"github.com/gin-gonic/gin"
+)
+
+// startServer serves router on a random local port and returns its base URL
+func startServer(t *testing.T, router *gin.Engine) (*http.Server, string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	srv := &http.Server{Handler: router}
+	go func() { _ = srv.Serve(listener) }()
+	return srv, "http://" + listener.Addr().String()
+}
+
+// testClient sends every request on a fresh connection so no idle or
+// speculatively dialed keep-alive connection holds up srv.Shutdown
+var testClient = &http.Client{
+	Timeout:   time.Second,
+	Transport: &http.Transport{DisableKeepAlives: true},
+}
+
+// get returns the status code of a GET to url, or 0 if the request failed
+func get(url string) int {
+	resp, err := testClient.Get(url)
+	if err != nil {
+		return 0
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestShutdownFailsReadinessDuringDrainWindow(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	h := health.NewHandler()
+	router := gin.New()
+	h.RegisterRoutes(router)
+	router.GET("/work", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	srv, base := startServer(t, router)
+	if code := get(base + "/ready"); code != http.StatusOK {
+		t.Fatalf("ready before shutdown = %d, want 200", code)
+	}
+
+	delay := 300 * time.Millisecond
+	cfg := config.ServerConfig{PreShutdownDelay: delay, ShutdownTimeout: 10 * time.Second}
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- Shutdown(srv, cfg, h) }()
+
+	time.Sleep(delay / 3)
+	if code := get(base + "/ready"); code != http.StatusServiceUnavailable {
+		t.Errorf("ready during drain window = %d, want 503", code)
+	}
+	if code := get(base + "/work"); code != http.StatusOK {
+		t.Errorf("request during drain window = %d, want 200", code)
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("shutdown returned before the drain delay: %v", err)
+	default:
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Errorf("shutdown took %v, want at least %v", elapsed, delay)
+	}
+	if code := get(base + "/work"); code != 0 {
+		t.Errorf("request after shutdown = %d, want connection failure", code)
+	}
+}
+
+func TestShutdownWithoutDelayOrHealthHandler(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	srv, _ := startServer(t, gin.New())
+
+	start := time.Now()
+	if err := Shutdown(srv, config.ServerConfig{}, nil); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("shutdown took %v without a delay", elapsed)
+	}
+}
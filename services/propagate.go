@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"net/http"
+)
+
+// propagatedHeadersKey is the context key for headers to forward downstream
+type propagatedHeadersKey struct{}
+
+// WithPropagatedHeaders returns a copy of ctx carrying the values of names
+// from header, for PropagationTransport to forward on outbound requests
+func WithPropagatedHeaders(ctx context.Context, header http.Header, names []string) context.Context {
+	propagated := make(http.Header, len(names))
+	for _, name := range names {
+		if values := header.Values(name); len(values) > 0 {
+			propagated[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+		}
+	}
+	if len(propagated) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, propagatedHeadersKey{}, propagated)
+}
+
+// PropagatedHeaders returns the headers stored by WithPropagatedHeaders
+func PropagatedHeaders(ctx context.Context) http.Header {
+	header, _ := ctx.Value(propagatedHeadersKey{}).(http.Header)
+	return header
+}
+
+// propagationTransport is an http.RoundTripper that forwards propagated headers
+type propagationTransport struct {
+	next http.RoundTripper
+}
+
+// NewPropagationTransport wraps next (http.DefaultTransport when nil) to set
+// the headers stored in the request context by WithPropagatedHeaders on each
+// outbound request. Headers the request already sets are left alone.
+func NewPropagationTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &propagationTransport{next: next}
+}
+
+// RoundTrip adds the propagated headers and sends req
+func (t *propagationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	propagated := PropagatedHeaders(req.Context())
+	if len(propagated) == 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	for name, values := range propagated {
+		if _, exists := req.Header[name]; !exists {
+			req.Header[name] = values
+		}
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// echoHeadersServer returns a server recording the headers of the last request
+func echoHeadersServer(t *testing.T) (*httptest.Server, *http.Header) {
+	t.Helper()
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func TestPropagationTransportForwardsConfiguredHeaders(t *testing.T) {
+	server, received := echoHeadersServer(t)
+	inbound := http.Header{}
+	inbound.Set("X-B3-Traceid", "463ac35c9f6413ad")
+	inbound.Set("x-ot-span-context", "span-1")
+	inbound.Set("Authorization", "Bearer user-token")
+	inbound.Set("Cookie", "session=1")
+
+	ctx := WithPropagatedHeaders(context.Background(), inbound, []string{"x-b3-traceid", "X-Ot-Span-Context", "X-B3-Sampled"})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	client := &http.Client{Transport: NewPropagationTransport(nil)}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if got := received.Get("X-B3-Traceid"); got != "463ac35c9f6413ad" {
+		t.Errorf("X-B3-Traceid = %q, want it propagated", got)
+	}
+	if got := received.Get("X-Ot-Span-Context"); got != "span-1" {
+		t.Errorf("X-Ot-Span-Context = %q, want it propagated", got)
+	}
+	for _, name := range []string{"Authorization", "Cookie", "X-B3-Sampled"} {
+		if got := received.Get(name); got != "" {
+			t.Errorf("%s = %q, want it not forwarded", name, got)
+		}
+	}
+	if len(req.Header) != 0 {
+		t.Errorf("caller's request modified: %v", req.Header)
+	}
+}
+
+func TestPropagationTransportKeepsExplicitHeaders(t *testing.T) {
+	server, received := echoHeadersServer(t)
+	inbound := http.Header{}
+	inbound.Set("X-Request-Id", "inbound-id")
+
+	ctx := WithPropagatedHeaders(context.Background(), inbound, []string{"X-Request-Id"})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	req.Header.Set("X-Request-Id", "explicit-id")
+	client := &http.Client{Transport: NewPropagationTransport(nil)}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if got := received.Get("X-Request-Id"); got != "explicit-id" {
+		t.Errorf("X-Request-Id = %q, want the header set on the request", got)
+	}
+}
+
+func TestWithPropagatedHeadersNoMatches(t *testing.T) {
+	ctx := context.Background()
+	if got := WithPropagatedHeaders(ctx, http.Header{"Cookie": {"a"}}, []string{"X-B3-Traceid"}); got != ctx {
+		t.Error("context wrapped although no header matched")
+	}
+	if got := PropagatedHeaders(ctx); got != nil {
+		t.Errorf("PropagatedHeaders() = %v, want nil", got)
+	}
+}
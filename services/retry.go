@@ -0,0 +1,144 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RetryConfig holds retry configuration for outbound service calls
+type RetryConfig struct {
+	MaxAttempts int           // total attempts including the first, at least 1
+	BaseBackoff time.Duration // wait before the first retry, doubled for each further retry
+	MaxBackoff  time.Duration // upper bound for a single wait
+
+	// Logger records each retry at warn level and exhausted retries at error
+	// level. Defaults to zap.L().
+	Logger *zap.Logger
+}
+
+// DefaultRetryConfig returns default retry configuration
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts: 3,
+		BaseBackoff: 100 * time.Millisecond,
+		MaxBackoff:  2 * time.Second,
+	}
+}
+
+// retryTransport is an http.RoundTripper that retries failed requests
+type retryTransport struct {
+	next http.RoundTripper
+	cfg  RetryConfig
+}
+
+// NewRetryTransport wraps next (http.DefaultTransport when nil) to retry
+// requests that fail with a network error or a 429, 502, 503 or 504 status.
+// Only idempotent methods and requests whose body can be replayed are retried.
+func NewRetryTransport(next http.RoundTripper, cfg RetryConfig) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = zap.L()
+	}
+	return &retryTransport{next: next, cfg: cfg}
+}
+
+// RoundTrip sends req, retrying with exponential backoff
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !retryable(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	logger := t.cfg.Logger.With(
+		zap.String("method", req.Method),
+		zap.String("url", req.URL.Redacted()),
+	)
+
+	for attempt := 1; ; attempt++ {
+		attemptReq := req
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to replay request body: %w", err)
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+		reason := retryReason(resp, err)
+		if reason == "" {
+			if attempt > 1 {
+				logger.Info("Outbound request succeeded after retry", zap.Int("attempts", attempt))
+			}
+			return resp, nil
+		}
+
+		if attempt == t.cfg.MaxAttempts {
+			logger.Error("Outbound request failed after all attempts",
+				zap.Int("attempts", attempt),
+				zap.String("reason", reason),
+			)
+			return resp, err
+		}
+
+		backoff := t.backoff(attempt)
+		logger.Warn("Retrying outbound request",
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.String("reason", reason),
+		)
+		if resp != nil {
+			// Drain so the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the wait after the given failed attempt
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	backoff := t.cfg.BaseBackoff << (attempt - 1)
+	if t.cfg.MaxBackoff > 0 && (backoff > t.cfg.MaxBackoff || backoff <= 0) {
+		backoff = t.cfg.MaxBackoff
+	}
+	return backoff
+}
+
+// retryable reports whether req can safely be sent more than once
+func retryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// retryReason describes why an attempt should be retried, or returns "" if it should not
+func retryReason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return fmt.Sprintf("status %d", resp.StatusCode)
+	}
+	return ""
+}
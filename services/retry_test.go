@@ -0,0 +1,150 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// flakyServer returns a server that responds 503 to the first failures
+// requests and 200 afterwards, and a counter of the requests it received
+func flakyServer(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+// retryClient returns a client retrying up to maxAttempts times, logging to the returned logs
+func retryClient(maxAttempts int) (*http.Client, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	transport := NewRetryTransport(nil, RetryConfig{
+		MaxAttempts: maxAttempts,
+		BaseBackoff: time.Millisecond,
+		MaxBackoff:  5 * time.Millisecond,
+		Logger:      zap.New(core),
+	})
+	return &http.Client{Transport: transport}, logs
+}
+
+func TestRetryTransportLogsEachRetry(t *testing.T) {
+	server, calls := flakyServer(t, 2)
+	client, logs := retryClient(3)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Fatalf("status = %d after %d calls, want 200 after 3", resp.StatusCode, calls.Load())
+	}
+
+	retries := logs.FilterMessage("Retrying outbound request").All()
+	if len(retries) != 2 {
+		t.Fatalf("got %d retry log entries, want 2", len(retries))
+	}
+	for i, entry := range retries {
+		fields := entry.ContextMap()
+		if entry.Level != zapcore.WarnLevel {
+			t.Errorf("retry %d logged at %s, want warn", i+1, entry.Level)
+		}
+		if fields["attempt"] != int64(i+1) || fields["reason"] != "status 503" {
+			t.Errorf("retry %d fields = %v, want attempt %d and reason status 503", i+1, fields, i+1)
+		}
+		if want := time.Millisecond << i; fields["backoff"] != want {
+			t.Errorf("retry %d backoff = %v, want %s", i+1, fields["backoff"], want)
+		}
+	}
+
+	success := logs.FilterMessage("Outbound request succeeded after retry").All()
+	if len(success) != 1 || success[0].ContextMap()["attempts"] != int64(3) {
+		t.Fatalf("success entries = %v, want one with attempts 3", success)
+	}
+}
+
+func TestRetryTransportLogsExhaustedRetries(t *testing.T) {
+	server, calls := flakyServer(t, 10)
+	client, logs := retryClient(2)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || calls.Load() != 2 {
+		t.Fatalf("status = %d after %d calls, want the last 503 after 2", resp.StatusCode, calls.Load())
+	}
+
+	failed := logs.FilterMessage("Outbound request failed after all attempts").All()
+	if len(failed) != 1 || failed[0].Level != zapcore.ErrorLevel {
+		t.Fatalf("failure entries = %v, want one at error level", failed)
+	}
+	if got := logs.FilterMessage("Retrying outbound request").Len(); got != 1 {
+		t.Errorf("got %d retry entries, want 1", got)
+	}
+}
+
+func TestRetryTransportReplaysBody(t *testing.T) {
+	server, calls := flakyServer(t, 1)
+	client, _ := retryClient(3)
+
+	req, _ := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("payload"))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if calls.Load() != 2 || string(body) != "payload" {
+		t.Fatalf("body %q after %d calls, want the replayed payload after 2", body, calls.Load())
+	}
+}
+
+func TestRetryTransportSkipsNonIdempotent(t *testing.T) {
+	server, calls := flakyServer(t, 1)
+	client, logs := retryClient(3)
+
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || calls.Load() != 1 {
+		t.Fatalf("status = %d after %d calls, want 503 without retrying", resp.StatusCode, calls.Load())
+	}
+	if logs.Len() != 0 {
+		t.Errorf("got %d log entries for a request that is not retried", logs.Len())
+	}
+}
+
+func TestRetryTransportNetworkError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+	client, logs := retryClient(2)
+
+	if _, err := client.Get(url); err == nil {
+		t.Fatal("Get() succeeded against a closed server")
+	}
+	retries := logs.FilterMessage("Retrying outbound request").All()
+	if len(retries) != 1 || retries[0].ContextMap()["reason"] == "" {
+		t.Errorf("retry entries = %v, want one with the network error as reason", retries)
+	}
+}
@@ -0,0 +1,62 @@
+// Package services provides helpers for calling other wealist services.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/config"
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/health"
+)
+
+// URL returns the configured base URL for a service name such as "auth" or "board"
+func URL(cfg config.ServicesConfig, name string) (string, bool) {
+	switch name {
+	case "auth":
+		return cfg.AuthServiceURL, true
+	case "user":
+		return cfg.UserServiceURL, true
+	case "board":
+		return cfg.BoardServiceURL, true
+	case "chat":
+		return cfg.ChatServiceURL, true
+	case "noti":
+		return cfg.NotiServiceURL, true
+	case "storage":
+		return cfg.StorageServiceURL, true
+	case "video":
+		return cfg.VideoServiceURL, true
+	default:
+		return "", false
+	}
+}
+
+// VerifyReachable checks that each required service answers GET /health with a
+// 2xx status and returns all failures joined into one error. Call it at startup
+// to refuse to boot when a critical downstream is unreachable.
+// client defaults to one using cfg.Timeout when nil.
+func VerifyReachable(ctx context.Context, cfg config.ServicesConfig, required []string, client *http.Client) error {
+	if client == nil {
+		client = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	var errs []error
+	for _, name := range required {
+		baseURL, ok := URL(cfg, name)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: unknown service", name))
+			continue
+		}
+		if baseURL == "" {
+			errs = append(errs, fmt.Errorf("%s: service URL not configured", name))
+			continue
+		}
+		checker := health.NewHTTPChecker(name, baseURL, 0).WithPath("/health").WithClient(client)
+		if check := checker.Check(ctx); check.Status != health.StatusHealthy {
+			errs = append(errs, fmt.Errorf("%s: %s", name, check.Message))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OrangesCloud/wealist-advanced-go-pkg/config"
+)
+
+// unreachableURL returns the URL of a server that has already been closed
+func unreachableURL() string {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	return srv.URL
+}
+
+func TestVerifyReachable(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+
+	cfg := config.ServicesConfig{
+		AuthServiceURL:  healthy.URL,
+		BoardServiceURL: unreachableURL(),
+		Timeout:         time.Second,
+	}
+
+	if err := VerifyReachable(context.Background(), cfg, []string{"auth"}, nil); err != nil {
+		t.Fatalf("reachable service: VerifyReachable() = %v, want nil", err)
+	}
+
+	err := VerifyReachable(context.Background(), cfg, []string{"auth", "board", "chat", "billing"}, nil)
+	if err == nil {
+		t.Fatal("VerifyReachable() = nil, want failures")
+	}
+	message := err.Error()
+	for _, want := range []string{"board:", "chat: service URL not configured", "billing: unknown service"} {
+		if !strings.Contains(message, want) {
+			t.Errorf("error %q does not mention %q", message, want)
+		}
+	}
+	if strings.Contains(message, "auth:") {
+		t.Errorf("error %q mentions the reachable service", message)
+	}
+}
+
+func TestVerifyReachableUnhealthyStatus(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	cfg := config.ServicesConfig{UserServiceURL: failing.URL, Timeout: time.Second}
+	if err := VerifyReachable(context.Background(), cfg, []string{"user"}, failing.Client()); err == nil {
+		t.Fatal("VerifyReachable() = nil for a 503 health endpoint")
+	}
+}